@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	// handlerTimeout bounds each request's context (HANDLER_TIMEOUT_SECONDS)
+	handlerTimeout = 60 * time.Second
+
+	// slowRequest is the duration above which a request is logged as slow
+	// (SLOW_REQUEST_MS)
+	slowRequest = 2 * time.Second
+)
+
+// streamingPaths are long-lived responses exempt from handlerTimeout.
+var streamingPaths = map[string]bool{
+	"/db-data/watch/events": true,
+	"/redis-data/export":    true,
+}
+
+// statusRecorder captures the status code and body size of a response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+	if s.status == 0 {
+		s.status = code
+	}
+	s.ResponseWriter.WriteHeader(code)
+}
+
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	if s.status == 0 {
+		s.status = http.StatusOK
+	}
+	n, err := s.ResponseWriter.Write(b)
+	s.bytes += int64(n)
+	return n, err
+}
+
+// Flush keeps SSE streaming (the watch view) working through the wrapper.
+func (s *statusRecorder) Flush() {
+	if f, ok := s.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
+
+// withDeadline cancels the request context after handlerTimeout, so a
+// handler's backend calls can't outlive it. Paths are matched after
+// BASE_PATH is stripped.
+func withDeadline(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if streamingPaths[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+		ctx, cancel := context.WithTimeout(r.Context(), handlerTimeout)
+		defer cancel()
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// queryParams lists the names of a request's query parameters. Values are
+// left out: they carry Redis keys, Mongo filters and share tokens.
+func queryParams(r *http.Request) []string {
+	q := r.URL.Query()
+	names := make([]string, 0, len(q))
+	for name := range q {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// accessLog logs each request at debug level as it starts and its method,
+// path, status, response size and duration once done, and warns with the
+// query parameter names when a request took over slowRequest. It wraps the
+// handler before BASE_PATH is stripped, so streamingPaths are matched on
+// the path without it.
+func accessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		slog.Debug("handling request", "method", r.Method, "path", r.URL.Path, "params", queryParams(r))
+		rec := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+		if rec.status == 0 {
+			rec.status = http.StatusOK
+		}
+		took := time.Since(start)
+		slog.Info("request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", rec.status,
+			"bytes", rec.bytes,
+			"duration_ms", took.Milliseconds(),
+		)
+		if took > slowRequest && !streamingPaths[strings.TrimPrefix(r.URL.Path, basePath)] {
+			slog.Warn("slow request",
+				"path", r.URL.Path,
+				"params", queryParams(r),
+				"duration_ms", took.Milliseconds(),
+			)
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"golang.org/x/sync/errgroup"
+)
+
+// reportACLCheck enables the public-report check on the list
+// (REPORT_ACL_CHECK=true, or ?acl=1 per request). It costs a GetObjectAcl
+// per report unless the bucket ignores public ACLs.
+var reportACLCheck bool
+
+// publicGroups are the grantees that make an object readable by anyone.
+var publicGroups = map[string]bool{
+	"http://acs.amazonaws.com/groups/global/AllUsers":           true,
+	"http://acs.amazonaws.com/groups/global/AuthenticatedUsers": true,
+}
+
+// markPublicReports sets Public on the reports of bucket whose ACL grants
+// read access to everyone. When the bucket's public access block ignores
+// public ACLs no object can be public through its ACL, so nothing is fetched.
+func markPublicReports(ctx context.Context, bucket string, reports []SimpleReportView) {
+	pab, err := s3Client().GetPublicAccessBlock(ctx, &s3.GetPublicAccessBlockInput{Bucket: aws.String(bucket)})
+	if err == nil && pab.PublicAccessBlockConfiguration != nil && aws.ToBool(pab.PublicAccessBlockConfiguration.IgnorePublicAcls) {
+		return
+	}
+
+	g := new(errgroup.Group)
+	g.SetLimit(8)
+	for i, rep := range reports {
+		g.Go(func() error {
+			out, err := s3Client().GetObjectAcl(ctx, &s3.GetObjectAclInput{
+				Bucket: aws.String(bucket),
+				Key:    aws.String(rep.Name),
+			})
+			countBackendError("s3", err)
+			if err != nil {
+				slog.Error("get acl failed", "backend", "s3", "key", rep.Name, "error", err)
+				return nil
+			}
+			reports[i].Public = publicGrant(out.Grants)
+			return nil
+		})
+	}
+	g.Wait()
+}
+
+// publicGrant reports whether grants let everyone read the object.
+func publicGrant(grants []types.Grant) bool {
+	for _, gr := range grants {
+		if gr.Grantee == nil || !publicGroups[aws.ToString(gr.Grantee.URI)] {
+			continue
+		}
+		switch gr.Permission {
+		case types.PermissionRead, types.PermissionFullControl:
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// activityWeeks is how far back the heatmap goes.
+const activityWeeks = 53
+
+// reportActivityHandler renders a calendar heatmap of reports per day
+// (by last-modified date, UTC) as inline SVG, so gaps in nightly runs
+// stand out.
+func reportActivityHandler(w http.ResponseWriter, r *http.Request) {
+	local := localReports()
+	if !local && s3Client() == nil {
+		content := `<div class="card"><h2>📅 Report Activity</h2><p style="color:#6b7280">S3 not configured.</p></div>`
+		page := layout("Report Activity", content, backendStatus())
+		fmt.Fprint(w, page)
+		return
+	}
+
+	counts := map[string]int{}
+	var err error
+	if local {
+		var views []SimpleReportView
+		views, err = listLocalReports()
+		for _, v := range views {
+			counts[strings.SplitN(v.Date, " ", 2)[0]]++
+		}
+	} else {
+		var reports []Report
+		reports, err = scanReports(r.Context(), s3Bucket, "", time.Time{})
+		for _, rep := range reports {
+			counts[rep.Date.UTC().Format("2006-01-02")]++
+		}
+	}
+	notice := ""
+	if err != nil {
+		slog.Error("report activity failed", "backend", "s3", "error", err)
+		notice = `<p style="color:#b45309">Listing incomplete — counts may be low.</p>`
+	}
+
+	content := fmt.Sprintf(`
+<div class="card">
+  <h2>📅 Report Activity</h2>
+  %s
+  <div style="margin-bottom:10px"><a href="/load-test">← Reports</a></div>
+  <div style="overflow-x:auto">%s</div>
+</div>
+`, notice, activitySVG(counts, time.Now().UTC()))
+
+	page := layout("Report Activity", content, backendStatus())
+	fmt.Fprint(w, page)
+}
+
+// activitySVG draws one column per week ending with today's week, one row
+// per weekday (Sunday first), shaded by the day's count relative to the
+// busiest day.
+func activitySVG(counts map[string]int, today time.Time) string {
+	const cell, gap, top, left = 11, 2, 16, 28
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	start := today.AddDate(0, 0, -int(today.Weekday())-7*(activityWeeks-1))
+
+	max, total, active := 0, 0, 0
+	for d := start; !d.After(today); d = d.AddDate(0, 0, 1) {
+		n := counts[d.Format("2006-01-02")]
+		if n > max {
+			max = n
+		}
+		total += n
+		if n > 0 {
+			active++
+		}
+	}
+
+	var b strings.Builder
+	width := left + activityWeeks*(cell+gap)
+	height := top + 7*(cell+gap)
+	fmt.Fprintf(&b, `<svg width="%d" height="%d" xmlns="http://www.w3.org/2000/svg" font-size="9" fill="#6b7280">`, width, height+20)
+	for i, name := range []string{"Mon", "Wed", "Fri"} {
+		fmt.Fprintf(&b, `<text x="0" y="%d">%s</text>`, top+(2*i+1)*(cell+gap)+cell-2, name)
+	}
+
+	lastMonth := time.Month(0)
+	for d := start; !d.After(today); d = d.AddDate(0, 0, 1) {
+		week := int(d.Sub(start).Hours()/24) / 7
+		x := left + week*(cell+gap)
+		y := top + int(d.Weekday())*(cell+gap)
+		if d.Weekday() == time.Sunday && d.Month() != lastMonth {
+			lastMonth = d.Month()
+			fmt.Fprintf(&b, `<text x="%d" y="10">%s</text>`, x, d.Format("Jan"))
+		}
+		day := d.Format("2006-01-02")
+		n := counts[day]
+		noun := "reports"
+		if n == 1 {
+			noun = "report"
+		}
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" rx="2" fill="%s"><title>%s: %d %s</title></rect>`,
+			x, y, cell, cell, activityColor(n, max), day, n, noun)
+	}
+	fmt.Fprintf(&b, `<text x="%d" y="%d">%d reports on %d of the last %d days</text>`, left, height+14, total, active, int(today.Sub(start).Hours()/24)+1)
+	b.WriteString(`</svg>`)
+	return b.String()
+}
+
+// activityColor picks one of five shades: empty, then quartiles of max.
+func activityColor(n, max int) string {
+	shades := []string{"#ebedf0", "#9be9a8", "#40c463", "#30a14e", "#216e39"}
+	if n == 0 || max == 0 {
+		return shades[0]
+	}
+	level := 1 + (n-1)*4/max
+	if level > 4 {
+		level = 4
+	}
+	return shades[level]
+}
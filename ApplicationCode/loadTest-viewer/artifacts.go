@@ -0,0 +1,109 @@
+package main
+
+import (
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// reportExtensions are the object suffixes listed as reports
+// (REPORT_EXTENSIONS, comma-separated). Only .html by default; runs that
+// also upload e.g. summary.json and results.csv can add those.
+var reportExtensions = []string{".html"}
+
+// previewKinds open through the inline object view (/load-test/object)
+// instead of a presigned URL; the browser would only download them.
+var previewKinds = map[string]bool{"json": true, "csv": true}
+
+// loadReportExtensions parses REPORT_EXTENSIONS. A missing leading dot is
+// added, so "html,json" works too.
+func loadReportExtensions(raw string) {
+	var exts []string
+	for _, e := range strings.Split(raw, ",") {
+		e = strings.ToLower(strings.TrimSpace(e))
+		if e == "" {
+			continue
+		}
+		if !strings.HasPrefix(e, ".") {
+			e = "." + e
+		}
+		exts = append(exts, e)
+	}
+	if len(exts) > 0 {
+		reportExtensions = exts
+	}
+}
+
+// isReportKey reports whether key has one of the report extensions.
+func isReportKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, e := range reportExtensions {
+		if strings.HasSuffix(key, e) {
+			return true
+		}
+	}
+	return false
+}
+
+// reportKind is the artifact type shown as a badge: the extension without
+// the dot, e.g. "csv".
+func reportKind(key string) string {
+	return strings.TrimPrefix(strings.ToLower(path.Ext(key)), ".")
+}
+
+// reportRun is the test run an artifact belongs to: its "folder", e.g.
+// "login/2024-05-01/" for login/2024-05-01/summary.json.
+func reportRun(key string) string {
+	if i := strings.LastIndex(key, "/"); i >= 0 {
+		return key[:i+1]
+	}
+	return ""
+}
+
+// groupedReports is whether the list is grouped by run, which only helps
+// when a run can have more than one artifact.
+func groupedReports() bool {
+	return len(reportExtensions) > 1
+}
+
+// previewURL links a json/csv artifact to the inline object view.
+func previewURL(bucket, key string) string {
+	q := url.Values{"key": {key}}
+	if bucket != s3Bucket {
+		q.Set("bucket", bucket)
+	}
+	// built at request time, so withBasePath can't see it
+	return basePath + "/load-test/object?" + q.Encode()
+}
+
+// groupByRun orders reports so each run's artifacts sit together. Runs are
+// ordered by their newest artifact; within a run the latest-first order is
+// kept.
+func groupByRun(reports []SimpleReportView) {
+	newest := make(map[string]time.Time)
+	for _, r := range reports {
+		if run := reportRun(r.Name); r.LastModified.After(newest[run]) {
+			newest[run] = r.LastModified
+		}
+	}
+	sort.SliceStable(reports, func(i, j int) bool {
+		ri, rj := reportRun(reports[i].Name), reportRun(reports[j].Name)
+		if ri == rj {
+			return false
+		}
+		if ni, nj := newest[ri], newest[rj]; !ni.Equal(nj) {
+			return ni.After(nj)
+		}
+		return ri < rj
+	})
+}
+
+// markRunStarts flags the first report of each run on a page, where the
+// template puts the run heading.
+func markRunStarts(reports []SimpleReportView) {
+	for i := range reports {
+		reports[i].RunStart = i == 0 || reports[i].Run != reports[i-1].Run
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// uiUser and uiPass (UI_USER, UI_PASS) protect every page with HTTP Basic
+// Auth. When either is unset the UI is open to anyone who can reach it.
+var uiUser, uiPass string
+
+// authExempt are the paths served without credentials, so the kubelet's
+// probes and Prometheus scrapes keep working; /metrics only carries counts
+// by route pattern and backend. Paths are matched after BASE_PATH is
+// stripped.
+var authExempt = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+	"/metrics": true,
+}
+
+// authExemptPrefixes are subtrees served without credentials: share links
+// are meant for external readers and carry their own HMAC (see share.go).
+var authExemptPrefixes = []string{"/load-test/s/"}
+
+// isAuthExempt reports whether r may skip basic auth. Besides the exempt
+// paths that covers CORS preflights to /api/, which browsers send without
+// credentials.
+func isAuthExempt(r *http.Request) bool {
+	if authExempt[r.URL.Path] {
+		return true
+	}
+	for _, p := range authExemptPrefixes {
+		if strings.HasPrefix(r.URL.Path, p) {
+			return true
+		}
+	}
+	return r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" &&
+		strings.HasPrefix(r.URL.Path, "/api/")
+}
+
+// loadUIAuth reads UI_USER and UI_PASS, warning loudly when they're unset.
+func loadUIAuth(user, pass string) {
+	uiUser, uiPass = user, pass
+	if uiUser == "" || uiPass == "" {
+		slog.Warn("UI_USER/UI_PASS not set — the UI is UNAUTHENTICATED and exposes database and Redis contents to anyone who can reach it")
+		return
+	}
+	slog.Info("basic auth enabled", "user", uiUser)
+}
+
+// requireAuth answers 401 with a WWW-Authenticate challenge unless the
+// request carries the UI_USER/UI_PASS credentials. It passes everything
+// through when auth isn't configured.
+func requireAuth(next http.Handler) http.Handler {
+	if uiUser == "" || uiPass == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isAuthExempt(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		user, pass, ok := r.BasicAuth()
+		if !ok || !credentialsMatch(user, pass) {
+			w.Header().Set("WWW-Authenticate", "Basic realm="+strconv.Quote(appName)+`, charset="UTF-8"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// credentialsMatch compares in constant time. Hashing first keeps the
+// comparison independent of the lengths involved.
+func credentialsMatch(user, pass string) bool {
+	u, wu := sha256.Sum256([]byte(user)), sha256.Sum256([]byte(uiUser))
+	p, wp := sha256.Sum256([]byte(pass)), sha256.Sum256([]byte(uiPass))
+	return subtle.ConstantTimeCompare(u[:], wu[:])&subtle.ConstantTimeCompare(p[:], wp[:]) == 1
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequireAuthShareLink(t *testing.T) {
+	uiUser, uiPass = "admin", "secret"
+	shareSecret = []byte("test-secret")
+	defer func() { uiUser, uiPass = "", "" }()
+
+	// stands in for sharedReportHandler without needing S3
+	h := requireAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, share := strings.CutPrefix(r.URL.Path, "/load-test/s/")
+		if _, _, ok := parseShareToken(token); share && !ok {
+			http.Error(w, "invalid share link", 404)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		header map[string]string
+		auth   bool
+		want   int
+	}{
+		{"valid share token", "GET", "/load-test/s/" + shareToken("reports", "login/run2.html"), nil, false, 200},
+		{"forged share token", "GET", "/load-test/s/" + shareToken("reports", "a.html") + "x", nil, false, 404},
+		{"page without credentials", "GET", "/load-test", nil, false, 401},
+		{"page with credentials", "GET", "/load-test", nil, true, 200},
+		{"metrics scrape", "GET", "/metrics", nil, false, 200},
+		{"api preflight", "OPTIONS", "/api/load-test", map[string]string{"Origin": "https://ci.example", "Access-Control-Request-Method": "GET"}, false, 200},
+		{"plain options", "OPTIONS", "/api/load-test", nil, false, 401},
+		{"page preflight", "OPTIONS", "/redis-data", map[string]string{"Access-Control-Request-Method": "POST"}, false, 401},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.path, nil)
+			for k, v := range tt.header {
+				r.Header.Set(k, v)
+			}
+			if tt.auth {
+				r.SetBasicAuth("admin", "secret")
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
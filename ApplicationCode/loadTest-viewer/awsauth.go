@@ -0,0 +1,59 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/smithy-go"
+)
+
+// s3Credentials is the SDK's credential cache for the S3 client, kept so
+// lapsed credentials can be invalidated and fetched again.
+var s3Credentials *aws.CredentialsCache
+
+// awsAuthCodes are the S3/STS error codes of expired or rejected
+// credentials, as opposed to a missing permission.
+var awsAuthCodes = map[string]bool{
+	"ExpiredToken":          true,
+	"ExpiredTokenException": true,
+	"InvalidToken":          true,
+	"TokenRefreshRequired":  true,
+	"InvalidClientTokenId":  true,
+	"InvalidAccessKeyId":    true,
+	"SignatureDoesNotMatch": true,
+}
+
+// isAWSAuthError reports whether err comes from expired or invalid AWS
+// credentials, including a failed credential refresh (e.g. IRSA).
+func isAWSAuthError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && awsAuthCodes[apiErr.ErrorCode()] {
+		return true
+	}
+	return strings.Contains(err.Error(), "failed to refresh cached credentials")
+}
+
+// renderCredsExpired invalidates the cached credentials, so the next
+// request fetches fresh ones, and explains what happened.
+func renderCredsExpired(w http.ResponseWriter, title string, err error) {
+	slog.Error("AWS credentials rejected", "backend", "s3", "error", err)
+	if s3Credentials != nil {
+		s3Credentials.Invalidate()
+	}
+	w.WriteHeader(http.StatusServiceUnavailable)
+	content := fmt.Sprintf(`<div class="card"><h2>%s</h2>
+<p style="color:#b91c1c">AWS credentials expired or were rejected. The viewer will fetch new ones on the next request; if this persists, refresh the IRSA token or role session.</p>
+<p style="color:#6b7280;font-size:13px">%s</p>
+<a href="javascript:location.reload()">↻ Retry</a></div>`,
+		template.HTMLEscapeString(title), template.HTMLEscapeString(err.Error()))
+	page := layout(title, content, backendStatus())
+	fmt.Fprint(w, page)
+}
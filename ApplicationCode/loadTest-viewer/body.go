@@ -0,0 +1,38 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// limitBody caps every request body at limits.MaxBodyBytes, so a pasted
+// pipeline or document can't exhaust memory. Reading past the cap fails
+// with *http.MaxBytesError, which parseForm turns into a 413 page.
+func limitBody(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Body != nil {
+			r.Body = http.MaxBytesReader(w, r.Body, limits.MaxBodyBytes)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// parseForm parses the request form for a handler that accepts POSTs. It
+// renders an error page and returns false when the body is too large or
+// malformed.
+func parseForm(w http.ResponseWriter, r *http.Request) bool {
+	err := r.ParseForm()
+	if err == nil {
+		return true
+	}
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		content := fmt.Sprintf(`<div class="card"><h2>Request too large</h2><p style="color:#b91c1c">The submitted form is larger than %d bytes (MAX_BODY_BYTES).</p></div>`, tooLarge.Limit)
+		fmt.Fprint(w, layout("Request too large", content, backendStatus()))
+		return false
+	}
+	http.Error(w, "invalid form: "+err.Error(), http.StatusBadRequest)
+	return false
+}
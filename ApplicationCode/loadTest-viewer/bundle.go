@@ -0,0 +1,194 @@
+package main
+
+import (
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// exportBundleHandler zips the evidence for an incident ticket into one
+// download: the report (?report=, ?bucket=), a collection sample
+// (?db=&name=&filter=&sample=) and a Redis value (?k= or ?key=), whichever
+// are given. Parts that fail are listed in manifest.json instead of
+// failing the whole bundle. Without parameters it shows a form.
+func exportBundleHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	report, coll, rkey := q.Get("report"), q.Get("name"), requestKey(r)
+	if report == "" && coll == "" && rkey == "" {
+		renderBundleForm(w)
+		return
+	}
+
+	manifest := map[string]interface{}{"created": time.Now().UTC().Format(time.RFC3339)}
+	var problems []string
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "bundle-"+time.Now().UTC().Format("20060102-150405")+".zip"))
+	zw := zip.NewWriter(w)
+
+	ctx := r.Context()
+	if report != "" {
+		bucket := q.Get("bucket")
+		if bucket == "" {
+			bucket = s3Bucket
+		}
+		manifest["report"] = map[string]string{"bucket": bucket, "key": report}
+		if err := bundleReport(ctx, zw, bucket, report); err != nil {
+			problems = append(problems, "report: "+err.Error())
+		}
+	}
+	if coll != "" {
+		if mongoClient() == nil {
+			problems = append(problems, "mongo: not configured")
+		} else {
+			dbName, err := requestDB(ctx, r)
+			manifest["collection"] = map[string]string{"db": dbName, "name": coll, "filter": q.Get("filter"), "sample": sampleStrategy(r)}
+			if err == nil {
+				err = bundleCollection(ctx, zw, dbName, coll, q.Get("filter"), sampleStrategy(r))
+			}
+			if err != nil {
+				problems = append(problems, "mongo: "+err.Error())
+			}
+		}
+	}
+	if rkey != "" {
+		manifest["redisKey"] = rkey
+		if err := bundleRedisKey(ctx, zw, rkey); err != nil {
+			problems = append(problems, "redis: "+err.Error())
+		}
+	}
+
+	manifest["problems"] = problems
+	if f, err := zw.Create("manifest.json"); err == nil {
+		b, _ := json.MarshalIndent(manifest, "", "  ")
+		f.Write(b)
+	}
+	zw.Close()
+}
+
+// bundleReport adds the report object, decompressed if stored gzipped.
+func bundleReport(ctx context.Context, zw *zip.Writer, bucket, key string) error {
+	if s3Client() == nil {
+		return fmt.Errorf("not configured")
+	}
+	if !allowedBucket(bucket) {
+		return fmt.Errorf("bucket %q is not configured", bucket)
+	}
+	obj, err := s3Client().GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return backendErr("s3", err)
+	}
+	defer obj.Body.Close()
+
+	var body io.Reader = obj.Body
+	if strings.EqualFold(aws.ToString(obj.ContentEncoding), "gzip") {
+		zr, err := gzip.NewReader(obj.Body)
+		if err != nil {
+			return err
+		}
+		defer zr.Close()
+		body = zr
+	}
+	f, err := zw.Create("report/" + path.Base(key))
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, body)
+	return err
+}
+
+// bundleCollection adds the sampled documents matching filter as relaxed
+// extended JSON, redacted as in the viewer.
+func bundleCollection(ctx context.Context, zw *zip.Writer, dbName, name, rawFilter, strategy string) error {
+	filter, err := parseFilter(rawFilter)
+	if err != nil {
+		return fmt.Errorf("invalid filter: %v", err)
+	}
+	cur, err := sampleDocs(ctx, mongoClient().Database(dbName).Collection(name), filter, strategy, 0, limits.MongoPageSize)
+	if err != nil {
+		return err
+	}
+	smp, err := readSample(ctx, cur)
+	if err != nil {
+		return err
+	}
+
+	f, err := zw.Create("mongo/" + dbName + "." + name + ".json")
+	if err != nil {
+		return err
+	}
+	io.WriteString(f, "[")
+	for i, doc := range redactDocs(smp.docs) {
+		b, err := bson.MarshalExtJSONIndent(doc, false, false, "  ", "  ")
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			io.WriteString(f, ",")
+		}
+		io.WriteString(f, "\n  ")
+		f.Write(b)
+	}
+	io.WriteString(f, "\n]\n")
+	if smp.truncated {
+		return fmt.Errorf("sample truncated at MAX_RESPONSE_BYTES after %d documents", len(smp.docs))
+	}
+	return nil
+}
+
+// bundleRedisKey adds the key's type, value and TTL as exported by
+// /redis-data/export.
+func bundleRedisKey(ctx context.Context, zw *zip.Writer, key string) error {
+	if redisClient() == nil {
+		return fmt.Errorf("not configured")
+	}
+	entry, err := exportRedisKey(ctx, key)
+	if err != nil {
+		return err
+	}
+	f, err := zw.Create("redis/value.json")
+	if err != nil {
+		return err
+	}
+	b, _ := json.MarshalIndent(map[string]redisExportEntry{key: entry}, "", "  ")
+	_, err = f.Write(b)
+	return err
+}
+
+func renderBundleForm(w http.ResponseWriter) {
+	content := `
+<div class="card">
+  <h2>📦 Export bundle</h2>
+  <p style="color:#6b7280;font-size:13px">Zips a report, a collection sample and a Redis value into one download for a ticket. Fill in any of them.</p>
+  <form method="get">
+    <h3>Report</h3>
+    <div class="row"><input name="report" class="search" placeholder="Report key, e.g. runs/2024-05-01/index.html"/></div>
+    <h3>MongoDB</h3>
+    <div class="row">
+      <input name="db" class="search" style="max-width:200px" placeholder="Database"/>
+      <input name="name" class="search" style="max-width:200px" placeholder="Collection"/>
+      <input name="filter" class="search" placeholder='Filter, e.g. {"status":"failed"}'/>
+    </div>
+    <h3>Redis</h3>
+    <div class="row"><input name="key" class="search" placeholder="Key"/></div>
+    <div style="margin-top:10px"><button class="copy-btn" type="submit">Download zip</button></div>
+  </form>
+</div>
+`
+	page := layout("Export bundle", content, backendStatus())
+	fmt.Fprint(w, page)
+}
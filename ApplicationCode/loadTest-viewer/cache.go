@@ -0,0 +1,191 @@
+package main
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// sampleCache is a small LRU of collection samples keyed by
+// db+collection+filter+page. Entries older than ttl are treated as misses.
+type sampleCache struct {
+	mu    sync.Mutex
+	size  int
+	ttl   time.Duration
+	order *list.List // front = most recently used
+	items map[string]*list.Element
+}
+
+// sample is a collection sample as read for display. truncated is set when
+// reading stopped at the MAX_RESPONSE_BYTES budget.
+type sample struct {
+	docs      []bson.M
+	truncated bool
+}
+
+type sampleEntry struct {
+	key    string
+	sample sample
+	stored time.Time
+}
+
+func newSampleCache(size int, ttl time.Duration) *sampleCache {
+	return &sampleCache{
+		size:  size,
+		ttl:   ttl,
+		order: list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+func (c *sampleCache) get(key string) (sample, time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return sample{}, time.Time{}, false
+	}
+	e := el.Value.(*sampleEntry)
+	if time.Since(e.stored) > c.ttl {
+		c.order.Remove(el)
+		delete(c.items, key)
+		return sample{}, time.Time{}, false
+	}
+	c.order.MoveToFront(el)
+	return e.sample, e.stored, true
+}
+
+func (c *sampleCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.order.Init()
+	c.items = make(map[string]*list.Element)
+}
+
+func (c *sampleCache) put(key string, s sample) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		e := el.Value.(*sampleEntry)
+		e.sample, e.stored = s, time.Now()
+		c.order.MoveToFront(el)
+		return
+	}
+	c.items[key] = c.order.PushFront(&sampleEntry{key: key, sample: s, stored: time.Now()})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*sampleEntry).key)
+	}
+}
+
+// tagCache remembers S3 object tags by report so ?tag= filtering doesn't
+// call GetObjectTagging for every candidate on every request. Keys include
+// the object's modification time, so an overwritten report is re-read.
+type tagCache struct {
+	mu     sync.Mutex
+	ttl    time.Duration
+	items  map[string]tagEntry
+	pruned time.Time
+}
+
+type tagEntry struct {
+	tags   map[string]string
+	stored time.Time
+}
+
+func newTagCache(ttl time.Duration) *tagCache {
+	return &tagCache{ttl: ttl, items: make(map[string]tagEntry)}
+}
+
+func (c *tagCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items = make(map[string]tagEntry)
+}
+
+func (c *tagCache) get(key string) (map[string]string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.items[key]
+	if !ok || time.Since(e.stored) > c.ttl {
+		return nil, false
+	}
+	return e.tags, true
+}
+
+func (c *tagCache) put(key string, tags map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// drop expired entries at most once per ttl
+	now := time.Now()
+	if now.Sub(c.pruned) > c.ttl {
+		for k, e := range c.items {
+			if now.Sub(e.stored) > c.ttl {
+				delete(c.items, k)
+			}
+		}
+		c.pruned = now
+	}
+	c.items[key] = tagEntry{tags: tags, stored: now}
+}
+
+// presignCache reuses presigned report URLs across page loads. An entry is
+// re-signed once half its lifetime has passed, so a listed link always has
+// at least half the requested expiry left, or when the object's
+// modification time no longer matches.
+type presignCache struct {
+	mu     sync.Mutex
+	items  map[string]presignEntry
+	pruned time.Time
+}
+
+type presignEntry struct {
+	url      string
+	modified time.Time
+	refresh  time.Time // re-sign from here on
+}
+
+func newPresignCache() *presignCache {
+	return &presignCache{items: make(map[string]presignEntry)}
+}
+
+func (c *presignCache) get(key string, modified time.Time) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.items[key]
+	if !ok || !e.modified.Equal(modified) || time.Now().After(e.refresh) {
+		return "", false
+	}
+	return e.url, true
+}
+
+func (c *presignCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items = make(map[string]presignEntry)
+}
+
+func (c *presignCache) put(key, url string, modified time.Time, expires time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// drop entries due for re-signing at most once a minute
+	now := time.Now()
+	if now.Sub(c.pruned) > time.Minute {
+		for k, e := range c.items {
+			if now.After(e.refresh) {
+				delete(c.items, k)
+			}
+		}
+		c.pruned = now
+	}
+	c.items[key] = presignEntry{url: url, modified: modified, refresh: now.Add(expires / 2)}
+}
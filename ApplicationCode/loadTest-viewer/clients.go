@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// The backend clients are set once at startup and never replaced: the
+// drivers reconnect on their own when a backend comes back. Handlers still
+// reach them only through these accessors, which keeps the reads safe
+// should a setter ever run while serving. A nil client means the backend
+// is not configured (or, for Mongo, its URI could not be used).
+var (
+	clientsMu sync.RWMutex
+	s3c       *s3.Client
+	s3p       *s3.PresignClient
+	mongoc    *mongo.Client
+	redisc    redis.UniversalClient // *redis.Client, or *redis.ClusterClient in cluster mode
+)
+
+func s3Client() *s3.Client {
+	clientsMu.RLock()
+	defer clientsMu.RUnlock()
+	return s3c
+}
+
+func s3Presign() *s3.PresignClient {
+	clientsMu.RLock()
+	defer clientsMu.RUnlock()
+	return s3p
+}
+
+func mongoClient() *mongo.Client {
+	clientsMu.RLock()
+	defer clientsMu.RUnlock()
+	return mongoc
+}
+
+func redisClient() redis.UniversalClient {
+	clientsMu.RLock()
+	defer clientsMu.RUnlock()
+	return redisc
+}
+
+// setS3Client installs c along with a presign client built from it.
+func setS3Client(c *s3.Client) {
+	var p *s3.PresignClient
+	if c != nil {
+		p = s3.NewPresignClient(c)
+	}
+	clientsMu.Lock()
+	s3c, s3p = c, p
+	clientsMu.Unlock()
+}
+
+func setMongoClient(c *mongo.Client) {
+	clientsMu.Lock()
+	mongoc = c
+	clientsMu.Unlock()
+}
+
+func setRedisClient(c redis.UniversalClient) {
+	clientsMu.Lock()
+	redisc = c
+	clientsMu.Unlock()
+}
+
+// redisLimiter is a go-redis hook bounding the number of in-flight Redis
+// commands and pipelines; callers wait for a slot or their context.
+type redisLimiter chan struct{}
+
+func (l redisLimiter) acquire(ctx context.Context) error {
+	select {
+	case l <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l redisLimiter) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (l redisLimiter) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if err := l.acquire(ctx); err != nil {
+			return err
+		}
+		defer func() { <-l }()
+		return next(ctx, cmd)
+	}
+}
+
+func (l redisLimiter) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if err := l.acquire(ctx); err != nil {
+			return err
+		}
+		defer func() { <-l }()
+		return next(ctx, cmds)
+	}
+}
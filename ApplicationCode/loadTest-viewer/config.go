@@ -0,0 +1,90 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Limits holds the per-backend page sizes and caps. Defaults suit a small
+// deployment; each can be tuned per environment through env vars.
+type Limits struct {
+	MongoPageSize      int64 // documents shown per collection page
+	MongoMaxPage       int64 // upper bound for any requested page size
+	RedisMaxKeys       int   // keys collected by the key-list scan
+	RedisMaxValueBytes int64 // strings above this are only previewed
+	RedisMaxElements   int64 // collections above this are only previewed
+	ReportPageSize     int32 // objects per ListObjectsV2 call (S3 caps at 1000)
+	ObjectMaxBytes     int64 // largest S3 object shown by the raw object view
+	MaxResponseBytes   int64 // documents read per collection page, in BSON bytes
+	RedisExportMaxKeys int64 // keys written by /redis-data/export
+	MaxBodyBytes       int64 // largest accepted request body
+	GzipMinBytes       int64 // responses from this size on are gzipped
+	RedisSearchMaxKeys int64 // keys read by /redis-data/search
+}
+
+var limits = Limits{
+	MongoPageSize:      200,
+	MongoMaxPage:       1000,
+	RedisMaxKeys:       1000,
+	RedisMaxValueBytes: 1 << 20,
+	RedisMaxElements:   1000,
+	ReportPageSize:     1000,
+	ObjectMaxBytes:     256 << 10,
+	MaxResponseBytes:   8 << 20,
+	RedisExportMaxKeys: 10000,
+	MaxBodyBytes:       1 << 20,
+	GzipMinBytes:       1400,
+	RedisSearchMaxKeys: 5000,
+}
+
+// loadLimits overrides the defaults from env. Invalid or non-positive
+// values are logged and ignored.
+func loadLimits() {
+	limits.MongoPageSize = envInt("MONGO_PAGE_SIZE", limits.MongoPageSize)
+	limits.MongoMaxPage = envInt("MONGO_MAX_PAGE", limits.MongoMaxPage)
+	limits.RedisMaxKeys = int(envInt("REDIS_MAX_KEYS", int64(limits.RedisMaxKeys)))
+	limits.RedisMaxValueBytes = envInt("REDIS_MAX_VALUE_BYTES", limits.RedisMaxValueBytes)
+	limits.RedisMaxElements = envInt("REDIS_MAX_ELEMENTS", limits.RedisMaxElements)
+	limits.ReportPageSize = int32(envInt("REPORT_PAGE_SIZE", int64(limits.ReportPageSize)))
+	limits.ObjectMaxBytes = envInt("OBJECT_MAX_BYTES", limits.ObjectMaxBytes)
+	limits.MaxResponseBytes = envInt("MAX_RESPONSE_BYTES", limits.MaxResponseBytes)
+	limits.RedisExportMaxKeys = envInt("REDIS_EXPORT_MAX_KEYS", limits.RedisExportMaxKeys)
+	limits.MaxBodyBytes = envInt("MAX_BODY_BYTES", limits.MaxBodyBytes)
+	limits.GzipMinBytes = envInt("GZIP_MIN_BYTES", limits.GzipMinBytes)
+	limits.RedisSearchMaxKeys = envInt("REDIS_SEARCH_MAX_KEYS", limits.RedisSearchMaxKeys)
+
+	if limits.MongoPageSize > limits.MongoMaxPage {
+		limits.MongoPageSize = limits.MongoMaxPage
+	}
+	if limits.ReportPageSize > 1000 {
+		limits.ReportPageSize = 1000
+	}
+}
+
+func envInt(name string, def int64) int64 {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return def
+	}
+	v, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || v <= 0 {
+		slog.Warn("ignoring invalid setting", "env", name, "value", raw, "default", def)
+		return def
+	}
+	return v
+}
+
+func envDuration(name string, def time.Duration) time.Duration {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return def
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		slog.Warn("ignoring invalid setting", "env", name, "value", raw, "default", def.String())
+		return def
+	}
+	return d
+}
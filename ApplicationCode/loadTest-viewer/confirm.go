@@ -0,0 +1,155 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// confirmWindow is how long a destructive action's confirmation stays
+// valid (CONFIRM_DESTRUCTIVE_SECONDS). Zero disables the second step.
+var confirmWindow time.Duration
+
+// confirmSecret signs confirmation tokens. They only live for seconds, so
+// a per-process secret is enough.
+var confirmSecret = func() []byte {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return b
+}()
+
+// usedConfirmations remembers spent tokens until they expire, so a
+// confirmed request can't be replayed.
+var usedConfirmations = struct {
+	sync.Mutex
+	m map[string]time.Time
+}{m: map[string]time.Time{}}
+
+// confirmed wraps a destructive POST handler in a two-step confirmation
+// when CONFIRM_DESTRUCTIVE_SECONDS is set (see confirmStep).
+func confirmed(describe func(r *http.Request) string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if confirmWindow == 0 {
+			next(w, r)
+			return
+		}
+		confirmStep(confirmWindow, describe, next)(w, r)
+	}
+}
+
+// confirmStep always requires the two-step confirmation: the first POST
+// answers with a page describing the action and a token signed over the
+// path, the form and an expiry; only resubmitting that form within window
+// runs next. Tokens are single-use.
+func confirmStep(window time.Duration, describe func(r *http.Request) string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			next(w, r)
+			return
+		}
+		if !parseForm(w, r) {
+			return
+		}
+
+		form := url.Values{}
+		for k, v := range r.PostForm {
+			if k != "confirm_token" {
+				form[k] = v
+			}
+		}
+		if token := r.PostForm.Get("confirm_token"); token != "" {
+			if err := spendConfirmation(token, r.URL.Path, form); err != nil {
+				w.WriteHeader(http.StatusForbidden)
+				content := `<div class="card"><h2>Not confirmed</h2><p style="color:#b91c1c">` + template.HTMLEscapeString(err.Error()) +
+					` — nothing was changed.</p><a href="javascript:history.go(-2)">← Back</a></div>`
+				fmt.Fprint(w, layout("Not confirmed", content, backendStatus()))
+				return
+			}
+			next(w, r)
+			return
+		}
+
+		expires := time.Now().Add(window)
+		var hidden strings.Builder
+		for k, vs := range form {
+			for _, v := range vs {
+				fmt.Fprintf(&hidden, `<input type="hidden" name="%s" value="%s"/>`, template.HTMLEscapeString(k), template.HTMLEscapeString(v))
+			}
+		}
+		secs := int(window / time.Second)
+		content := fmt.Sprintf(`
+<div class="card">
+  <h2>⚠ Confirm</h2>
+  <p style="color:#b45309">%s</p>
+  <form method="post">
+    %s
+    <input type="hidden" name="confirm_token" value="%s"/>
+    <button id="confirmBtn" class="copy-btn" type="submit" style="background:#b91c1c">Yes, do it</button>
+    <a href="javascript:history.back()" style="margin-left:8px">Cancel</a>
+    <span id="confirmLeft" style="margin-left:8px;color:#6b7280;font-size:13px">%d s left</span>
+  </form>
+  <script>
+    (function () {
+      var left = %d;
+      var t = setInterval(function () {
+        left--;
+        document.getElementById("confirmLeft").textContent = left > 0 ? left + " s left" : "expired — go back and try again";
+        if (left <= 0) { clearInterval(t); document.getElementById("confirmBtn").disabled = true; }
+      }, 1000);
+    })();
+  </script>
+</div>
+`, template.HTMLEscapeString(describe(r)), hidden.String(), confirmationToken(r.URL.Path, form, expires), secs, secs)
+		fmt.Fprint(w, layout("Confirm", content, backendStatus()))
+	}
+}
+
+// confirmationToken is "<expiry unix>.<base64url HMAC>" over the path, the
+// expiry and the form (url.Values.Encode sorts it, so it's canonical).
+func confirmationToken(path string, form url.Values, expires time.Time) string {
+	exp := strconv.FormatInt(expires.Unix(), 10)
+	return exp + "." + base64.RawURLEncoding.EncodeToString(confirmMAC(path, exp, form))
+}
+
+func confirmMAC(path, exp string, form url.Values) []byte {
+	m := hmac.New(sha256.New, confirmSecret)
+	m.Write([]byte(path + "\x00" + exp + "\x00" + form.Encode()))
+	return m.Sum(nil)
+}
+
+// spendConfirmation checks a token against the request and marks it used.
+func spendConfirmation(token, path string, form url.Values) error {
+	exp, sig, ok := strings.Cut(token, ".")
+	mac, err := base64.RawURLEncoding.DecodeString(sig)
+	if !ok || err != nil || !hmac.Equal(mac, confirmMAC(path, exp, form)) {
+		return fmt.Errorf("the confirmation doesn't match this request")
+	}
+	unix, _ := strconv.ParseInt(exp, 10, 64)
+	expires := time.Unix(unix, 0)
+	if time.Now().After(expires) {
+		return fmt.Errorf("the confirmation expired")
+	}
+
+	usedConfirmations.Lock()
+	defer usedConfirmations.Unlock()
+	now := time.Now()
+	for t, e := range usedConfirmations.m {
+		if now.After(e) {
+			delete(usedConfirmations.m, t)
+		}
+	}
+	if _, used := usedConfirmations.m[token]; used {
+		return fmt.Errorf("the confirmation was already used")
+	}
+	usedConfirmations.m[token] = expires
+	return nil
+}
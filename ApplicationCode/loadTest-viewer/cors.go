@@ -0,0 +1,50 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+)
+
+// corsOrigins is the CORS_ORIGINS allow-list for the /api/ routes ("*"
+// allows any origin). Empty means no cross-origin access.
+var corsOrigins []string
+
+func loadCORSOrigins(raw string) {
+	for _, o := range strings.Split(raw, ",") {
+		if o = strings.TrimRight(strings.TrimSpace(o), "/"); o != "" {
+			corsOrigins = append(corsOrigins, o)
+		}
+	}
+}
+
+func corsAllowed(origin string) bool {
+	for _, o := range corsOrigins {
+		if o == "*" || o == origin {
+			return true
+		}
+	}
+	return false
+}
+
+// withCORS adds CORS headers for allowed origins and answers OPTIONS
+// preflight requests. Only the JSON /api/ routes are wrapped.
+func withCORS(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin != "" && corsAllowed(origin) {
+			h := w.Header()
+			h.Set("Access-Control-Allow-Origin", origin)
+			h.Add("Vary", "Origin")
+			if r.Method == http.MethodOptions {
+				h.Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+				h.Set("Access-Control-Allow-Headers", r.Header.Get("Access-Control-Request-Headers"))
+				h.Set("Access-Control-Max-Age", "600")
+			}
+		}
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next(w, r)
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+)
+
+// csrfToken is embedded in state-changing forms and checked on submit by
+// every POST handler in redis_write.go. A cross-site page can post to the
+// viewer with the user's credentials but can't read a page to learn the
+// token. It is random per process, so open forms need a reload after a
+// restart.
+var csrfToken = func() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}()
+
+// csrfField is the hidden input carrying csrfToken.
+func csrfField() string {
+	return `<input type="hidden" name="csrf" value="` + csrfToken + `"/>`
+}
+
+// checkCSRF reports whether a parsed POST carries csrfToken and, when the
+// browser sends an Origin, that it is this host.
+func checkCSRF(r *http.Request) bool {
+	if o := r.Header.Get("Origin"); o != "" {
+		if u, err := url.Parse(o); err != nil || u.Host != r.Host {
+			return false
+		}
+	}
+	return hmac.Equal([]byte(r.PostForm.Get("csrf")), []byte(csrfToken))
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// docStream writes documents to a <pre> block one at a time, as the same
+// JSON array text marshalView would produce for all of them, but as HTML:
+// each _id links to the single-document view and each scalar top-level
+// value gets a "filter by this value" action that narrows the current
+// filter. The actions are drawn with CSS (a.qf::after), so copying the
+// <pre> still yields plain JSON. Values are shown as extended JSON,
+// canonical when asked, so ObjectIDs and dates keep their type.
+type docStream struct {
+	w         io.Writer
+	indent    string
+	canonical bool
+	dbName    string
+	name      string
+	filter    bson.M
+	n         int // documents written
+}
+
+// write appends one document to the array.
+func (s *docStream) write(doc bson.M) {
+	nl, sep := "\n", ": "
+	if s.indent == "" {
+		nl, sep = "", ":"
+	}
+	indent := s.indent
+	keys := make([]string, 0, len(doc))
+	for k := range doc {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	if s.n == 0 {
+		b.WriteString("[" + nl)
+	} else {
+		b.WriteString("," + nl)
+	}
+	b.WriteString(indent + "{" + nl)
+	for j, k := range keys {
+		v := doc[k]
+		kj, _ := json.Marshal(k)
+		vj := marshalViewPrefix(extJSON(v, s.canonical), indent+indent, indent)
+		b.WriteString(indent + indent + template.HTMLEscapeString(string(kj)) + sep)
+		if k == "_id" {
+			fmt.Fprintf(&b, `<a href="%s" style="color:inherit">%s</a>`,
+				template.HTMLEscapeString(documentLink(s.dbName, s.name, v)), template.HTMLEscapeString(vj))
+		} else {
+			b.WriteString(template.HTMLEscapeString(vj))
+		}
+		if href := filterByValueLink(s.dbName, s.name, s.filter, k, v); href != "" {
+			fmt.Fprintf(&b, `<a class="qf" href="%s" title="Filter by %s = this value"></a>`,
+				template.HTMLEscapeString(href), template.HTMLEscapeString(k))
+		}
+		if j < len(keys)-1 {
+			b.WriteString(",")
+		}
+		b.WriteString(nl)
+	}
+	b.WriteString(indent + "}")
+	io.WriteString(s.w, withBasePath(b.String()))
+	s.n++
+}
+
+// close ends the array.
+func (s *docStream) close() {
+	switch {
+	case s.n == 0:
+		io.WriteString(s.w, "[]")
+	case s.indent == "":
+		io.WriteString(s.w, "]")
+	default:
+		io.WriteString(s.w, "\n]")
+	}
+}
+
+// extCanonical reports whether ?ext=canonical asks for canonical extended
+// JSON; documents are shown as relaxed extended JSON otherwise.
+func extCanonical(r *http.Request) bool {
+	return r.URL.Query().Get("ext") == "canonical"
+}
+
+// extJSON converts a BSON value to its extended JSON form ({"$oid": ...},
+// {"$date": ...}) as plain maps, slices and json.Numbers, so marshalView
+// lays it out with sorted keys like any other value. v is returned as is
+// if it can't be converted.
+func extJSON(v interface{}, canonical bool) interface{} {
+	b, err := bson.MarshalExtJSON(bson.D{{Key: "v", Value: v}}, canonical, false)
+	if err != nil {
+		return v
+	}
+	var wrap struct {
+		V interface{} `json:"v"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if dec.Decode(&wrap) != nil {
+		return v
+	}
+	return wrap.V
+}
+
+// extToggle links to the current view with ?ext switched between relaxed
+// and canonical extended JSON.
+func extToggle(r *http.Request) string {
+	q := r.URL.Query()
+	label := "Canonical"
+	if extCanonical(r) {
+		q.Del("ext")
+		label = "Relaxed"
+	} else {
+		q.Set("ext", "canonical")
+	}
+	return fmt.Sprintf(`<a href="%s?%s" style="margin-left:8px" title="Extended JSON mode">$ %s</a>`,
+		template.HTMLEscapeString(r.URL.Path), template.HTMLEscapeString(q.Encode()), label)
+}
+
+// marshalViewPrefix is marshalView for a value nested under prefix.
+func marshalViewPrefix(v interface{}, prefix, indent string) string {
+	var b []byte
+	if indent == "" {
+		b, _ = json.Marshal(v)
+	} else {
+		b, _ = json.MarshalIndent(v, prefix, indent)
+	}
+	return string(b)
+}
+
+// filterByValueLink returns the collection URL with filter narrowed to
+// field == v, or "" for values that can't be filtered on from here:
+// documents, arrays and redacted fields.
+func filterByValueLink(dbName, name string, filter bson.M, field string, v interface{}) string {
+	switch v.(type) {
+	case bson.M, bson.D, bson.A, map[string]interface{}, []interface{}:
+		return ""
+	}
+	if field == "_id" || redactedField(field) {
+		return ""
+	}
+	narrowed := bson.M{}
+	for k, fv := range filter {
+		narrowed[k] = fv
+	}
+	narrowed[field] = v
+	fj, err := bson.MarshalExtJSON(narrowed, false, false)
+	if err != nil {
+		return ""
+	}
+	return "/db-data/collection?" + url.Values{"db": {dbName}, "name": {name}, "filter": {string(fj)}}.Encode()
+}
+
+// documentLink links to the single-document view of the document with the
+// given _id. The id travels as canonical extended JSON so its BSON type
+// survives the round trip.
+func documentLink(dbName, name string, id interface{}) string {
+	ij, err := bson.MarshalExtJSON(bson.M{"_id": id}, true, false)
+	if err != nil {
+		return ""
+	}
+	return "/db-data/document?" + url.Values{"db": {dbName}, "name": {name}, "id": {string(ij)}}.Encode()
+}
+
+// dbDocumentHandler shows one document, looked up by the ?id= built by
+// documentLink.
+func dbDocumentHandler(w http.ResponseWriter, r *http.Request) {
+	if mongoClient() == nil {
+		content := `<div class="card"><h2>Document</h2><p style="color:#6b7280">Mongo not configured.</p></div>`
+		page := layout("Document", content, backendStatus())
+		fmt.Fprint(w, page)
+		return
+	}
+
+	name := r.URL.Query().Get("name")
+	var idFilter bson.M
+	if err := bson.UnmarshalExtJSON([]byte(r.URL.Query().Get("id")), true, &idFilter); err != nil || len(idFilter) != 1 || idFilter["_id"] == nil {
+		http.Error(w, "missing or invalid id", 400)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), backendTimeout)
+	defer cancel()
+	dbName, err := requestDB(ctx, r)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	title := "Document " + docID(idFilter["_id"])
+
+	var doc bson.M
+	err = backendErr("mongo", mongoClient().Database(dbName).Collection(name).FindOne(ctx, idFilter).Decode(&doc))
+	if isTimeout(err) {
+		renderTimeout(w, title)
+		return
+	}
+	body := ""
+	if err != nil {
+		body = `<p style="color:#6b7280">` + template.HTMLEscapeString(err.Error()) + `</p>`
+	} else {
+		body = `<pre id="jsonData" class="json">` + template.HTMLEscapeString(string(marshalView(extJSON(redactValue(doc), extCanonical(r)), jsonIndent(r)))) + `</pre>`
+	}
+
+	content := fmt.Sprintf(`
+<div class="card">
+  <h2>📄 %s</h2>
+  <div style="margin-bottom:10px">
+    <a href="/db-data/collection?%s">← %s</a>
+    <button class="copy-btn" onclick="copyTextById('jsonData')">Copy JSON</button>
+    <button class="copy-btn" onclick="copyViewLink()">🔗 Copy link</button>
+    %s
+  </div>
+  %s
+</div>
+`, template.HTMLEscapeString(title),
+		template.HTMLEscapeString(url.Values{"db": {dbName}, "name": {name}}.Encode()), template.HTMLEscapeString(name),
+		compactToggle(r)+extToggle(r), body)
+
+	page := layout(title, content, backendStatus())
+	fmt.Fprint(w, page)
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// The collection view's <pre> carries the _id and filter-by-value links;
+// the JSON tree must leave such a block alone rather than hide it.
+func TestCollectionLinksSurviveJSONTree(t *testing.T) {
+	var b strings.Builder
+	ds := &docStream{w: &b, indent: "  ", dbName: "app", name: "runs", filter: bson.M{}}
+	ds.write(bson.M{"_id": int32(7), "status": "ok"})
+	ds.close()
+	docs := b.String()
+
+	for _, want := range []string{`href="/db-data/document?`, `class="qf" href="/db-data/collection?`} {
+		if !strings.Contains(docs, want) {
+			t.Errorf("documents lack %s:\n%s", want, docs)
+		}
+	}
+
+	page := layout("Collection: runs", `<pre id="jsonData" class="json">`+docs+`</pre>`, BackendStatus{})
+	if !strings.Contains(page, `<a class="qf"`) {
+		t.Error("page lost the filter-by-value links")
+	}
+	if !strings.Contains(page, `if (pre.querySelector("a")) {`) {
+		t.Error("jsonTree doesn't skip blocks with links, so the tree would hide them")
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strings"
+)
+
+// gzipResponses compresses responses for clients that accept gzip once
+// they reach limits.GzipMinBytes. Smaller responses, non-200 statuses,
+// already-encoded bodies (the report proxy) and streaming paths are
+// written as they are.
+func gzipResponses(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead || streamingPaths[r.URL.Path] || !acceptsGzip(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Add("Vary", "Accept-Encoding")
+		gw := &gzipWriter{ResponseWriter: w}
+		defer gw.close()
+		next.ServeHTTP(gw, r)
+	})
+}
+
+// compressible reports whether a content type is worth compressing.
+func compressible(ct string) bool {
+	ct, _, _ = strings.Cut(ct, ";")
+	ct = strings.TrimSpace(ct)
+	switch ct {
+	case "application/json", "application/javascript", "image/svg+xml":
+		return true
+	}
+	return strings.HasPrefix(ct, "text/")
+}
+
+// gzipWriter buffers the start of a response until it knows whether to
+// compress it: either the buffer reaches the threshold or the handler
+// returns or flushes.
+type gzipWriter struct {
+	http.ResponseWriter
+	status int
+	buf    []byte
+	gz     *gzip.Writer // set once compressing
+	plain  bool         // set once passing through
+}
+
+func (g *gzipWriter) WriteHeader(code int) {
+	if g.status != 0 {
+		return
+	}
+	g.status = code
+	if code != http.StatusOK || g.Header().Get("Content-Encoding") != "" {
+		g.plain = true
+		g.ResponseWriter.WriteHeader(code)
+	}
+}
+
+func (g *gzipWriter) Write(b []byte) (int, error) {
+	if g.status == 0 {
+		g.WriteHeader(http.StatusOK)
+	}
+	switch {
+	case g.plain:
+		return g.ResponseWriter.Write(b)
+	case g.gz != nil:
+		return g.gz.Write(b)
+	}
+	g.buf = append(g.buf, b...)
+	if int64(len(g.buf)) < limits.GzipMinBytes {
+		return len(b), nil
+	}
+	if err := g.start(); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// start sends the headers and the buffered bytes, compressed when the
+// content type allows.
+func (g *gzipWriter) start() error {
+	h := g.Header()
+	if h.Get("Content-Type") == "" {
+		h.Set("Content-Type", http.DetectContentType(g.buf))
+	}
+	if h.Get("Content-Encoding") != "" || !compressible(h.Get("Content-Type")) {
+		return g.passThrough()
+	}
+	h.Set("Content-Encoding", "gzip")
+	h.Del("Content-Length")
+	g.ResponseWriter.WriteHeader(g.status)
+	g.gz = gzip.NewWriter(g.ResponseWriter)
+	_, err := g.gz.Write(g.buf)
+	g.buf = nil
+	return err
+}
+
+// passThrough gives up on compressing and writes out the buffer.
+func (g *gzipWriter) passThrough() error {
+	g.plain = true
+	g.ResponseWriter.WriteHeader(g.status)
+	_, err := g.ResponseWriter.Write(g.buf)
+	g.buf = nil
+	return err
+}
+
+// Flush sends what is buffered so far; a response flushed before the
+// threshold is not compressed.
+func (g *gzipWriter) Flush() {
+	switch {
+	case g.gz != nil:
+		g.gz.Flush()
+	case !g.plain && g.status != 0:
+		g.passThrough()
+	}
+	if f, ok := g.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (g *gzipWriter) Unwrap() http.ResponseWriter {
+	return g.ResponseWriter
+}
+
+// close finishes the response once the handler returns.
+func (g *gzipWriter) close() {
+	switch {
+	case g.gz != nil:
+		g.gz.Close()
+	case !g.plain && g.status != 0:
+		g.passThrough()
+	}
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// CollMatch is a collection found by the inspect search.
+type CollMatch struct {
+	DB   string
+	Name string
+}
+
+// parseInspectQuery splits "report:foo", "coll:bar" or "key:baz" into the
+// backend and the term. Without a known prefix every backend is searched.
+func parseInspectQuery(raw string) (scope, term string) {
+	raw = strings.TrimSpace(raw)
+	if p, rest, ok := strings.Cut(raw, ":"); ok {
+		switch p {
+		case "report", "coll", "key":
+			return p, strings.TrimSpace(rest)
+		}
+	}
+	return "all", raw
+}
+
+// searchCollections matches q against the collection names of every
+// non-system database.
+func searchCollections(ctx context.Context, q string) ([]CollMatch, error) {
+	dbs, err := mongoClient().ListDatabaseNames(ctx, bson.M{})
+	if err != nil {
+		return nil, backendErr("mongo", err)
+	}
+	sort.Strings(dbs)
+	var out []CollMatch
+	for _, d := range dbs {
+		if isSystemDB(d) {
+			continue
+		}
+		cols, err := mongoClient().Database(d).ListCollectionNames(ctx, bson.M{})
+		if err != nil {
+			return out, backendErr("mongo", err)
+		}
+		sort.Strings(cols)
+		for _, c := range cols {
+			if matchesQuery(c, q) {
+				out = append(out, CollMatch{DB: d, Name: c})
+			}
+		}
+	}
+	return out, nil
+}
+
+// inspectPage renders the cross-backend search.
+const inspectPage = `
+<div class="card">
+  <h2>🔎 Inspect</h2>
+  <form method="get" class="row">
+    <input name="q" value="{{.Raw}}" class="search" placeholder="report:foo · coll:bar · key:baz · or any text to search everything"/>
+    <button class="copy-btn" type="submit">Search</button>
+  </form>
+  {{range .Problems}}<p style="color:#b45309">{{.}}</p>{{end}}
+
+  {{if .Term}}
+  {{if and .S3On (or (eq .Scope "all") (eq .Scope "report"))}}
+  <h3>📊 Reports ({{len .Reports}})</h3>
+  <div class="list">
+  {{range .Reports}}
+    <div class="list-item">
+      <div><a href="/load-test/open?bucket={{.Bucket}}&key={{.Name}}" target="_blank">{{highlight .Name $.Term}}</a></div>
+      <div class="badge">{{.Date.Format "2006-01-02 15:04"}}</div>
+    </div>
+  {{end}}
+  </div>
+  {{end}}
+
+  {{if and .MongoOn (or (eq .Scope "all") (eq .Scope "coll"))}}
+  <h3>🗄 Collections ({{len .Colls}})</h3>
+  <div class="list">
+  {{range .Colls}}
+    <div class="list-item">
+      <div><a href="/db-data/collection?db={{.DB}}&name={{.Name}}">{{highlight .Name $.Term}}</a></div>
+      <div class="badge">{{.DB}}</div>
+    </div>
+  {{end}}
+  </div>
+  {{end}}
+
+  {{if and .RedisOn (or (eq .Scope "all") (eq .Scope "key"))}}
+  <h3>⚡ Redis keys ({{len .Keys}})</h3>
+  <div class="list">
+  {{range .Keys}}
+    <div class="list-item">
+      <div><a href="/redis-data/key?k={{encodeKey .}}">{{highlight . $.Term}}</a></div>
+    </div>
+  {{end}}
+  </div>
+  {{end}}
+  {{end}}
+</div>
+`
+
+// inspectHandler answers the sidebar search: it routes the query to S3,
+// MongoDB and/or Redis by prefix and shows the matches from each.
+func inspectHandler(w http.ResponseWriter, r *http.Request) {
+	raw := r.URL.Query().Get("q")
+	scope, term := parseInspectQuery(raw)
+
+	var (
+		reports  []Report
+		colls    []CollMatch
+		keys     []string
+		problems []string
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+	)
+	fail := func(backend string, err error) {
+		slog.Error("inspect failed", "backend", backend, "error", err)
+		mu.Lock()
+		problems = append(problems, backend+": "+err.Error())
+		mu.Unlock()
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), backendTimeout)
+	defer cancel()
+	if term != "" {
+		if (scope == "all" || scope == "report") && s3Client() != nil {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				var failed []string
+				reports, failed = searchReports(ctx, term)
+				for _, b := range failed {
+					mu.Lock()
+					problems = append(problems, "S3: bucket "+b+" could not be searched")
+					mu.Unlock()
+				}
+			}()
+		}
+		if (scope == "all" || scope == "coll") && mongoClient() != nil {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				var err error
+				if colls, err = searchCollections(ctx, term); err != nil {
+					fail("MongoDB", err)
+				}
+			}()
+		}
+		if (scope == "all" || scope == "key") && redisClient() != nil {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				var err error
+				if keys, err = scanRedisKeys(ctx, "*", term, limits.RedisMaxKeys); err != nil {
+					fail("Redis", err)
+				}
+			}()
+		}
+		wg.Wait()
+	}
+
+	renderPage(w, "inspect", map[string]interface{}{
+		"Raw":      raw,
+		"Scope":    scope,
+		"Term":     term,
+		"Reports":  reports,
+		"Colls":    colls,
+		"Keys":     keys,
+		"Problems": problems,
+		"S3On":     s3Client() != nil,
+		"MongoOn":  mongoClient() != nil,
+		"RedisOn":  redisClient() != nil,
+	})
+}
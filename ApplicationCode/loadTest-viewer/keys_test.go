@@ -0,0 +1,69 @@
+package main
+
+import (
+	"html"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var testKeys = []string{
+	"user:1&foo",
+	"a b c",
+	"ключ:1",
+	"emoji:🔑",
+	"q?x=1#frag",
+	"100%+plus",
+	`quote"<tag>'`,
+	"bin\x00\xff",
+}
+
+func TestRequestKeyRoundTrip(t *testing.T) {
+	for _, key := range testKeys {
+		t.Run(key, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/redis-data/key?k="+encodeKey(key), nil)
+			if got := requestKey(r); got != key {
+				t.Errorf("?k=: got %q, want %q", got, key)
+			}
+			r = httptest.NewRequest("GET", "/redis-data/key?key="+url.QueryEscape(key), nil)
+			if got := requestKey(r); got != key {
+				t.Errorf("?key=: got %q, want %q", got, key)
+			}
+		})
+	}
+}
+
+var keyLink = regexp.MustCompile(`href="(/redis-data/key\?[^"]*)"`)
+
+func TestRedisKeyLinks(t *testing.T) {
+	tmpl, err := parsePage("redis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	views := make([]KeyView, len(testKeys))
+	for i, k := range testKeys {
+		views[i] = KeyView{Name: k}
+	}
+	var out strings.Builder
+	if err := tmpl.Execute(&out, map[string]interface{}{
+		"Keys":    views,
+		"Q":       "",
+		"DBSize":  int64(-1),
+		"MaxKeys": limits.RedisMaxKeys,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	links := keyLink.FindAllStringSubmatch(out.String(), -1)
+	if len(links) != len(testKeys) {
+		t.Fatalf("found %d key links, want %d", len(links), len(testKeys))
+	}
+	for i, m := range links {
+		r := httptest.NewRequest("GET", html.UnescapeString(m[1]), nil)
+		if got := requestKey(r); got != testKeys[i] {
+			t.Errorf("link %s: got %q, want %q", m[1], got, testKeys[i])
+		}
+	}
+}
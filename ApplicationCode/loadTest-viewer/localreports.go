@@ -0,0 +1,69 @@
+package main
+
+import (
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"sort"
+)
+
+// reportsDir is REPORTS_DIR: a local or NFS directory of reports,
+// used instead of S3 when no bucket is configured.
+var reportsDir string
+
+// localReports reports whether reports come from reportsDir.
+func localReports() bool {
+	return reportsDir != "" && s3Client() == nil
+}
+
+// listLocalReports walks reportsDir for report files, latest first. Links
+// point at the /load-test/files/ file server.
+func listLocalReports() ([]SimpleReportView, error) {
+	type found struct {
+		view SimpleReportView
+		mod  int64
+	}
+	var all []found
+	err := filepath.WalkDir(reportsDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !isReportKey(d.Name()) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(reportsDir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		all = append(all, found{
+			view: SimpleReportView{
+				Name:         rel,
+				URL:          basePath + "/load-test/files/" + (&url.URL{Path: rel}).EscapedPath(),
+				Date:         info.ModTime().Format("2006-01-02 15:04"),
+				LastModified: info.ModTime(),
+				Kind:         reportKind(rel),
+				Run:          reportRun(rel),
+			},
+			mod: info.ModTime().UnixNano(),
+		})
+		return nil
+	})
+
+	sort.Slice(all, func(i, j int) bool { return all[i].mod > all[j].mod })
+	out := make([]SimpleReportView, len(all))
+	for i, f := range all {
+		out[i] = f.view
+	}
+	return out, err
+}
+
+// localFilesHandler serves the files under reportsDir.
+func localFilesHandler() http.Handler {
+	return http.StripPrefix("/load-test/files/", http.FileServer(http.Dir(reportsDir)))
+}
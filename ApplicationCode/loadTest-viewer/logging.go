@@ -0,0 +1,26 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// setupLogging installs the default slog logger: JSON lines for the
+// central logging stack unless LOG_FORMAT=text (for local runs), at
+// LOG_LEVEL (debug, info, warn or error; info by default). Anything still
+// written through the log package goes through the same handler at info.
+func setupLogging(format, level string) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil || level == "" {
+		lvl = slog.LevelInfo
+	}
+	opts := &slog.HandlerOptions{Level: lvl}
+	var h slog.Handler
+	if strings.EqualFold(format, "text") {
+		h = slog.NewTextHandler(os.Stderr, opts)
+	} else {
+		h = slog.NewJSONHandler(os.Stderr, opts)
+	}
+	slog.SetDefault(slog.New(h))
+}
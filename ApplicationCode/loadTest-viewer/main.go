@@ -1,67 +1,197 @@
 package main
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/base64"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
-	"log"
+	"io"
+	"log/slog"
+	"mime"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
+	"path"
+	"regexp"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 
 	"github.com/redis/go-redis/v9"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/connstring"
+	"golang.org/x/sync/errgroup"
 )
 
 // --------- globals ----------
 var (
-	s3Client    *s3.Client
-	s3Presign   *s3.PresignClient
-	s3Bucket    string
-	mongoURI    string
-	redisURL    string
-	mongoClient *mongo.Client
-	redisClient *redis.Client
+	s3Bucket string
+	mongoURI string
+	redisURL string
+
+	// database path of DATABASE_URL (mongodb://host/mydb), if present
+	mongoDefaultDB string
+
+	// HeadObject each report to show its S3 user-metadata by default
+	reportMetadata bool
+
+	// REPORT_ENRICH HeadObjects every listed report for size, content type
+	// and user-metadata
+	reportEnrich bool
+
+	// when set, only these prefixes are listed (concurrently)
+	reportPrefixes []string
+
+	// collection sample cache, nil unless SAMPLE_CACHE_TTL is set
+	samples *sampleCache
+
+	// S3 object tags of listed reports, for ?tag= filtering
+	reportTags = newTagCache(10 * time.Minute)
+
+	// presigned URLs of listed reports, reused across page loads
+	reportURLs = newPresignCache()
+
+	// path prefix when mounted behind a proxy, e.g. "/tools/aiops" ("" = root)
+	basePath string
+
+	// every bucket the viewer may read (S3_BUCKET plus S3_BUCKETS); selectable
+	// on /load-test and searched by /load-test/search
+	s3Buckets []string
+
+	// ALLOW_WRITES enables the Redis write forms, key deletion included.
+	// ALLOW_REDIS_WRITE is still accepted as a legacy alias.
+	redisWrite bool
+
+	// ALLOW_FLUSH additionally enables /redis-data/flush
+	redisFlush bool
+
+	// SCAN pattern of the key list when no ?match= is given
+	redisDefaultMatch = "*"
+
+	// APP_NAME identifies our Mongo and Redis connections (currentOp,
+	// CLIENT LIST)
+	appName = "ollamaverse-viewer"
+
+	// sidebar branding: APP_TITLE, APP_SUBTITLE and APP_LOGO_URL
+	appTitle    = "AIOps Studio"
+	appSubtitle = "Observability & Tools"
+	appLogo     string
 )
 
 // --------- types ----------
 type Report struct {
-	Name string
-	URL  string
-	Date time.Time
+	Name   string    `json:"name"`
+	URL    string    `json:"url"`
+	Date   time.Time `json:"lastModified"`
+	Size   int64     `json:"size"`
+	Bucket string    `json:"bucket,omitempty"`
 }
 
 type SimpleReportView struct {
-	Name string
-	URL  string
-	Date string
+	Name         string            `json:"name"`
+	URL          string            `json:"url"`
+	Date         string            `json:"-"` // LastModified formatted for the page
+	LastModified time.Time         `json:"lastModified"`
+	Metadata     map[string]string `json:"metadata,omitempty"`    // S3 user-metadata, only when requested
+	DownloadURL  string            `json:"downloadUrl,omitempty"` // presigned to save rather than open
+	Public       bool              `json:"public,omitempty"`      // readable by anyone via its ACL, only when checked
+	Size         int64             `json:"size,omitempty"`        // bytes, only with REPORT_ENRICH
+	ContentType  string            `json:"contentType,omitempty"` // only with REPORT_ENRICH
+	Kind         string            `json:"kind"`                  // extension, e.g. "html" or "csv"
+	Run          string            `json:"run,omitempty"`         // test run prefix the artifact belongs to
+	PreviewURL   string            `json:"-"`                     // inline view for json/csv artifacts
+	RunStart     bool              `json:"-"`                     // first of its run on the page
 }
 
 type ColView struct {
 	Name     string
-	RowCount int64
+	RowCount int64  // -1 when the count could not be computed
 	Sample   string // preformatted JSON (escaped)
+	System   bool
+	Kind     string // "collection", "view" or "timeseries"
+	Detail   string // source of a view, time field of a time-series
+}
+
+// viewCountTimeout bounds counting a view or time-series collection, which
+// runs its pipeline rather than reading collection metadata.
+const viewCountTimeout = 2 * time.Second
+
+// collectionKind returns the kind of a listCollections entry and a short
+// description: the source collection of a view, or the time field and
+// granularity of a time-series collection.
+func collectionKind(spec *mongo.CollectionSpecification) (kind, detail string) {
+	switch spec.Type {
+	case "view":
+		if on, ok := spec.Options.Lookup("viewOn").StringValueOK(); ok {
+			detail = "on " + on
+		}
+		return "view", detail
+	case "timeseries":
+		ts, _ := spec.Options.Lookup("timeseries").DocumentOK()
+		if f, ok := ts.Lookup("timeField").StringValueOK(); ok {
+			detail = "time: " + f
+		}
+		if g, ok := ts.Lookup("granularity").StringValueOK(); ok {
+			detail += " · " + g
+		}
+		return "timeseries", detail
+	}
+	return "collection", ""
+}
+
+// collectionCount estimates the documents in a collection from metadata.
+// Views and time-series collections have no such count, so they are counted
+// with a short time limit and report -1 when that fails.
+func collectionCount(ctx context.Context, coll *mongo.Collection, kind string) int64 {
+	if kind == "collection" {
+		cnt, err := coll.EstimatedDocumentCount(ctx)
+		countBackendError("mongo", err)
+		return cnt
+	}
+	cnt, err := coll.CountDocuments(ctx, bson.M{}, options.Count().SetMaxTime(viewCountTimeout))
+	countBackendError("mongo", err)
+	if err != nil {
+		slog.Error("count failed", "backend", "mongo", "collection", coll.Name(), "kind", kind, "error", err)
+		return -1
+	}
+	return cnt
 }
 
 // --------- layout helper ----------
-// layout returns a full HTML page string with a sidebar and content placeholder.
-// content may include Go template directives (e.g. {{range .}}) — they'll be parsed later.
-func layout(title string, content string) string {
-	return fmt.Sprintf(`<!doctype html>
+// layout returns a full HTML page string with a sidebar and the given
+// content. status drives the connectivity dots next to each sidebar item.
+// Pages using template directives are parsed once through pageLayout
+// instead (see pages).
+func layout(title string, content string, status BackendStatus) string {
+	return shell(template.HTMLEscapeString(title), content, navLinks(status))
+}
+
+// shell is the page around content: title is HTML (or template text) for
+// the <title>, nav the sidebar links.
+func shell(title, content, nav string) string {
+	return withBasePath(fmt.Sprintf(`<!doctype html>
 <html>
 <head>
   <meta charset="utf-8">
   <meta name="viewport" content="width=device-width,initial-scale=1">
-  <title>%s</title>
+  <title>%s · %s</title>
   <style>
     :root {
       --bg: #f4f6fa;
@@ -93,6 +223,12 @@ func layout(title string, content string) string {
       font-size:15px;
     }
     .nav a.active, .nav a:hover { background:#04243f; color:white; }
+    .dot { display:inline-block; width:8px; height:8px; border-radius:50%%; margin-left:6px; vertical-align:middle; }
+    .dot-ok { background:#22c55e; }
+    .dot-down { background:#ef4444; }
+    .dot-off { background:#64748b; }
+    .dot-unknown { background:#f59e0b; }
+    .nav-badge { display:inline-block; margin-left:6px; padding:1px 6px; border-radius:999px; background:#0b3b66; color:#cfe6ff; font-size:11px; vertical-align:middle; }
     .content {
       flex:1;
       padding: 28px;
@@ -129,6 +265,11 @@ func layout(title string, content string) string {
     }
     .list-item a { color:var(--primary); font-weight:600; text-decoration:none; }
     .badge { background:var(--primary); color:white; padding:6px 10px; border-radius:999px; font-size:13px; }
+    .chips { display:flex; flex-wrap:wrap; gap:6px; margin-top:6px; }
+    .chip { background:#e0ecff; color:#1e3a8a; padding:3px 8px; border-radius:999px; font-size:12px; }
+    a.qf { margin-left:6px; color:#93c5fd; text-decoration:none; opacity:.6; }
+    a.qf:hover { opacity:1; }
+    a.qf::after { content:"⧩"; }
     pre.json {
       background: #0f1724;
       color: #dbeafe;
@@ -140,6 +281,24 @@ func layout(title string, content string) string {
       white-space:pre-wrap;
       word-break:break-word;
     }
+    .jt {
+      background: #0f1724;
+      color: #dbeafe;
+      padding: 14px;
+      border-radius:8px;
+      overflow:auto;
+      font-family: ui-monospace, SFMono-Regular, Menlo, monospace;
+      font-size:13px;
+      line-height:1.45;
+      word-break:break-word;
+    }
+    .jt-body { padding-left:18px; }
+    .jt-tog { cursor:pointer; user-select:none; }
+    .jt-tog:hover { color:white; }
+    .jt-key { color:#93c5fd; }
+    .jt-string { color:#86efac; }
+    .jt-number { color:#fcd34d; }
+    .jt-boolean, .jt-null { color:#f9a8d4; }
     .copy-btn {
       background:var(--primary);
       color:white;
@@ -169,6 +328,153 @@ func layout(title string, content string) string {
       }
     }
 
+    // reload the current view with ?nocache=1, bypassing every cache
+    function refreshView() {
+      var u = new URL(window.location.href);
+      u.searchParams.set("nocache", "1");
+      window.location.href = u.toString();
+    }
+
+    // copyViewLink copies the current URL, carrying the client-side list
+    // filter along as ?find= so whoever opens it sees the same view
+    function copyViewLink() {
+      var u = new URL(window.location.href);
+      u.searchParams.delete("nocache");
+      var s = document.querySelector("input.search");
+      if (s && s.value) {
+        u.searchParams.set("find", s.value);
+      } else {
+        u.searchParams.delete("find");
+      }
+      try {
+        navigator.clipboard.writeText(u.toString());
+        alert("Link copied to clipboard");
+      } catch(e){
+        alert("Copy failed");
+      }
+    }
+
+    // show the bucket a report page was asked for in the sidebar badge
+    document.addEventListener("DOMContentLoaded", function() {
+      var b = new URL(window.location.href).searchParams.get("bucket");
+      var el = document.getElementById("nav-load-badge");
+      if (b && el) {
+        el.textContent = b;
+      }
+    });
+
+    // restore a shared ?find= filter into the page's search box
+    document.addEventListener("DOMContentLoaded", function() {
+      var f = new URL(window.location.href).searchParams.get("find");
+      var s = document.querySelector("input.search");
+      if (f && s) {
+        s.value = f;
+        s.dispatchEvent(new Event("keyup"));
+      }
+    });
+
+    // render JSON shown in pre.json as a collapsible tree. The pre stays
+    // in the page as the raw view, which Copy reads. Children are built on
+    // first expand, so deep or huge documents cost nothing until opened.
+    function jsonNode(v, depth) {
+      if (v === null || typeof v !== "object") {
+        var leaf = document.createElement("span");
+        leaf.className = "jt-" + (v === null ? "null" : typeof v);
+        leaf.textContent = JSON.stringify(v);
+        return leaf;
+      }
+      var isArr = Array.isArray(v);
+      var keys = Object.keys(v);
+      var open = isArr ? "[" : "{", close = isArr ? "]" : "}";
+      var box = document.createElement("span");
+      if (keys.length === 0) {
+        box.textContent = open + close;
+        return box;
+      }
+      var head = document.createElement("span");
+      head.className = "jt-tog";
+      var tail = document.createElement("span");
+      box.appendChild(head);
+      box.appendChild(tail);
+      var body = null, expanded = false;
+      function set(on) {
+        if (on && !body) {
+          body = document.createElement("div");
+          body.className = "jt-body";
+          keys.forEach(function(k, i) {
+            var row = document.createElement("div");
+            if (!isArr) {
+              var ks = document.createElement("span");
+              ks.className = "jt-key";
+              ks.textContent = JSON.stringify(k) + ": ";
+              row.appendChild(ks);
+            }
+            row.appendChild(jsonNode(v[k], depth + 1));
+            if (i < keys.length - 1) {
+              row.appendChild(document.createTextNode(","));
+            }
+            body.appendChild(row);
+          });
+          box.insertBefore(body, tail);
+        }
+        if (body) {
+          body.style.display = on ? "" : "none";
+        }
+        head.textContent = on ? "▾ " + open : "▸ " + open + " " + keys.length + (isArr ? " items " : " keys ") + close;
+        tail.textContent = on ? close : "";
+        expanded = on;
+      }
+      head.onclick = function() { set(!expanded); };
+      set(depth < 2 && keys.length <= 100);
+      return box;
+    }
+
+    function jsonTree(pre) {
+      // blocks carrying links (a collection's _id and filter-by-value
+      // links) stay as they are; the tree is rebuilt from text and would
+      // drop them
+      if (pre.querySelector("a")) {
+        return;
+      }
+      var v;
+      try {
+        v = JSON.parse(pre.textContent);
+      } catch (e) {
+        return;
+      }
+      if (v === null || typeof v !== "object") {
+        return;
+      }
+      var tree = document.createElement("div");
+      tree.className = "jt";
+      tree.appendChild(jsonNode(v, 0));
+      var btn = document.createElement("button");
+      btn.className = "copy-btn";
+      btn.style.margin = "0 0 8px 0";
+      btn.textContent = "Raw";
+      btn.onclick = function() {
+        var raw = pre.style.display === "none";
+        pre.style.display = raw ? "" : "none";
+        tree.style.display = raw ? "none" : "";
+        btn.textContent = raw ? "Tree" : "Raw";
+      };
+      var wrap = document.createElement("div");
+      wrap.style.flex = "1";
+      wrap.style.minWidth = "0";
+      pre.parentNode.insertBefore(wrap, pre);
+      wrap.appendChild(btn);
+      wrap.appendChild(tree);
+      wrap.appendChild(pre);
+      pre.style.display = "none";
+    }
+
+    document.addEventListener("DOMContentLoaded", function() {
+      var pres = document.querySelectorAll("pre.json");
+      for (var i = 0; i < pres.length; i++) {
+        jsonTree(pres[i]);
+      }
+    });
+
     function filterList(inputId, itemClass) {
       var q = document.getElementById(inputId).value.toLowerCase();
       var items = document.getElementsByClassName(itemClass);
@@ -186,14 +492,16 @@ func layout(title string, content string) string {
 <body>
   <div class="app">
     <div class="sidebar">
-      <div class="brand">AIOps Studio</div>
-      <div style="font-size:13px;color:#9fb7d6;margin-bottom:12px">Observability & Tools</div>
+      <div class="brand">%s%s</div>
+      <div style="font-size:13px;color:#9fb7d6;margin-bottom:12px">%s</div>
+      <form action="/inspect" method="get" style="margin-bottom:12px">
+        <input name="q" class="inspect" placeholder="report: coll: key: …" title="Find an identifier in S3, MongoDB or Redis" style="width:100%%;box-sizing:border-box;padding:7px 9px;border-radius:6px;border:0"/>
+      </form>
       <div class="nav">
-        <a href="/load-test" id="nav-load">📊 Load Test Reports</a>
-        <a href="/db-data" id="nav-db">🗄 MongoDB Viewer</a>
-        <a href="/redis-data" id="nav-redis">⚡ Redis Viewer</a>
-      </div>
+%s      </div>
       <div style="flex:1"></div>
+      <a href="/export/bundle" title="Zip a report, collection sample and Redis value for a ticket" style="color:#cfe6ff;font-size:13px;text-decoration:none;margin-bottom:6px">📦 Export bundle</a>
+      <a href="javascript:refreshView()" title="Reload this view, bypassing caches" style="color:#cfe6ff;font-size:13px;text-decoration:none;margin-bottom:10px">↻ Refresh view</a>
       <div style="font-size:12px;color:#7f8ea3">Server UI · Built-in</div>
     </div>
 
@@ -202,403 +510,3173 @@ func layout(title string, content string) string {
     </div>
   </div>
 </body>
-</html>`, template.HTMLEscapeString(title), content)
+</html>`, title, template.HTMLEscapeString(appTitle),
+		brandLogo(), template.HTMLEscapeString(appTitle), template.HTMLEscapeString(appSubtitle),
+		nav,
+		content))
+}
+
+// brandLogo renders APP_LOGO_URL ahead of the title, if set.
+func brandLogo() string {
+	if appLogo == "" {
+		return ""
+	}
+	return `<img src="` + template.HTMLEscapeString(appLogo) + `" alt="" style="height:22px;vertical-align:middle;margin-right:8px"/>`
+}
+
+// withBasePath prefixes root-relative href/action/src attributes with
+// BASE_PATH so the UI keeps working when mounted under a sub-path.
+// Template actions are still unexpanded at this point, so only literal
+// links are rewritten; dynamic ones must include basePath themselves.
+func withBasePath(html string) string {
+	if basePath == "" {
+		return html
+	}
+	return strings.NewReplacer(
+		`href="/`, `href="`+basePath+`/`,
+		`action="/`, `action="`+basePath+`/`,
+		`src="/`, `src="`+basePath+`/`,
+	).Replace(html)
+}
+
+// --------- timeouts ----------
+
+// backendTimeout bounds each Mongo/Redis handler's backend calls
+// (BACKEND_TIMEOUT_SECONDS). They also run on the request context, so a client
+// navigating away cancels them early.
+var backendTimeout = 15 * time.Second
+
+// isTimeout reports whether err is a backend call running out of time.
+func isTimeout(err error) bool {
+	if err == nil {
+		return false
+	}
+	var ne net.Error
+	return errors.Is(err, context.DeadlineExceeded) || mongo.IsTimeout(err) ||
+		(errors.As(err, &ne) && ne.Timeout())
+}
+
+// renderTimeout renders an actionable card for a backend call that ran
+// past backendTimeout, instead of a generic error or blank page.
+func renderTimeout(w http.ResponseWriter, title string) {
+	content := fmt.Sprintf(`<div class="card"><h2>%s</h2><p style="color:#b45309">Query timed out after %s — try a narrower filter or smaller page.</p></div>`,
+		template.HTMLEscapeString(title), backendTimeout)
+	page := layout(title, content, backendStatus())
+	fmt.Fprint(w, page)
+}
+
+// --------- caches ----------
+
+// noCache reports whether the request asks to bypass caches (?nocache=1,
+// sent by the "Refresh view" control).
+func noCache(r *http.Request) bool {
+	return r.URL.Query().Get("nocache") == "1"
+}
+
+// --------- search helpers ----------
+
+// humanBytes formats n with a binary unit, e.g. "1.5 MiB".
+func humanBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// matchesQuery reports whether s contains q, ignoring case.
+func matchesQuery(s, q string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(q))
+}
+
+// highlight escapes s and wraps each case-insensitive match of q in <mark>.
+// Escaping happens per segment, so neither s nor q can inject markup.
+func highlight(s, q string) template.HTML {
+	if q == "" {
+		return template.HTML(template.HTMLEscapeString(s))
+	}
+	re := regexp.MustCompile("(?i)" + regexp.QuoteMeta(q))
+	var b strings.Builder
+	last := 0
+	for _, m := range re.FindAllStringIndex(s, -1) {
+		b.WriteString(template.HTMLEscapeString(s[last:m[0]]))
+		b.WriteString("<mark>")
+		b.WriteString(template.HTMLEscapeString(s[m[0]:m[1]]))
+		b.WriteString("</mark>")
+		last = m[1]
+	}
+	b.WriteString(template.HTMLEscapeString(s[last:]))
+	return template.HTML(b.String())
+}
+
+// --------- JSON formatting ----------
+
+// jsonIndent returns the indent for JSON views: "" (compact) with
+// ?compact=true, N spaces with ?indent=N (1-8), a tab with ?indent=tab,
+// and two spaces otherwise.
+func jsonIndent(r *http.Request) string {
+	q := r.URL.Query()
+	if q.Get("compact") == "true" {
+		return ""
+	}
+	switch raw := q.Get("indent"); {
+	case raw == "tab":
+		return "\t"
+	case raw != "":
+		if n, err := strconv.Atoi(raw); err == nil && n >= 1 && n <= 8 {
+			return strings.Repeat(" ", n)
+		}
+	}
+	return "  "
+}
+
+// marshalView encodes v for display, compact when indent is "".
+func marshalView(v interface{}, indent string) []byte {
+	var b []byte
+	if indent == "" {
+		b, _ = json.Marshal(v)
+	} else {
+		b, _ = json.MarshalIndent(v, "", indent)
+	}
+	return b
+}
+
+// compactToggle links to the current view with ?compact flipped.
+func compactToggle(r *http.Request) string {
+	q := r.URL.Query()
+	label := "Compact"
+	if q.Get("compact") == "true" {
+		q.Del("compact")
+		label = "Pretty"
+	} else {
+		q.Set("compact", "true")
+	}
+	return fmt.Sprintf(`<a href="%s?%s" style="margin-left:8px">{ } %s</a>`,
+		template.HTMLEscapeString(r.URL.Path), template.HTMLEscapeString(q.Encode()), label)
 }
 
 // --------- main ----------
 func main() {
+	// LOG_FORMAT=text for local runs; JSON otherwise
+	setupLogging(os.Getenv("LOG_FORMAT"), os.Getenv("LOG_LEVEL"))
+
 	// envs
+	// S3_BUCKETS lists every selectable bucket; S3_BUCKET, or else the
+	// first of them, is the default
 	s3Bucket = os.Getenv("S3_BUCKET")
+	if s3Bucket != "" {
+		s3Buckets = []string{s3Bucket}
+	}
+	for _, b := range strings.Split(os.Getenv("S3_BUCKETS"), ",") {
+		if b = strings.TrimSpace(b); b != "" && !slices.Contains(s3Buckets, b) {
+			s3Buckets = append(s3Buckets, b)
+		}
+	}
+	if s3Bucket == "" && len(s3Buckets) > 0 {
+		s3Bucket = s3Buckets[0]
+	}
+	reportMetadata = os.Getenv("REPORT_METADATA") == "true"
+	reportEnrich = os.Getenv("REPORT_ENRICH") == "true"
+	reportACLCheck = os.Getenv("REPORT_ACL_CHECK") == "true"
+	loadReportExtensions(os.Getenv("REPORT_EXTENSIONS"))
+	loadPresignExpiry()
+	reportsDir = os.Getenv("REPORTS_DIR")
+	loadShareSecret(os.Getenv("SHARE_SECRET"))
+	loadUIAuth(os.Getenv("UI_USER"), os.Getenv("UI_PASS"))
+	redisWrite = os.Getenv("ALLOW_WRITES") == "true" || os.Getenv("ALLOW_REDIS_WRITE") == "true"
+	redisFlush = os.Getenv("ALLOW_FLUSH") == "true"
+	if m := os.Getenv("REDIS_DEFAULT_MATCH"); m != "" {
+		redisDefaultMatch = m
+	}
+	if n := os.Getenv("APP_NAME"); n != "" {
+		appName = n
+	}
+	if t := os.Getenv("APP_TITLE"); t != "" {
+		appTitle = t
+	}
+	if t := os.Getenv("APP_SUBTITLE"); t != "" {
+		appSubtitle = t
+	}
+	appLogo = os.Getenv("APP_LOGO_URL")
+	if os.Getenv("HANDLER_TIMEOUT_SECONDS") != "" {
+		handlerTimeout = time.Duration(envInt("HANDLER_TIMEOUT_SECONDS", 60)) * time.Second
+	}
+	if os.Getenv("BACKEND_TIMEOUT_SECONDS") != "" {
+		backendTimeout = time.Duration(envInt("BACKEND_TIMEOUT_SECONDS", 15)) * time.Second
+	}
+	redisSearchTimeout = envDuration("REDIS_SEARCH_TIMEOUT", redisSearchTimeout)
+	redisExportTimeout = envDuration("REDIS_EXPORT_TIMEOUT", redisExportTimeout)
+	if os.Getenv("SLOW_REQUEST_MS") != "" {
+		slowRequest = time.Duration(envInt("SLOW_REQUEST_MS", 2000)) * time.Millisecond
+	}
+	// destructive actions ask for a second, timed confirmation
+	if os.Getenv("CONFIRM_DESTRUCTIVE_SECONDS") != "" {
+		confirmWindow = time.Duration(envInt("CONFIRM_DESTRUCTIVE_SECONDS", 30)) * time.Second
+	}
+	loadRedactFields(os.Getenv("REDACT_FIELDS"))
+	loadCORSOrigins(os.Getenv("CORS_ORIGINS"))
+	basePath = strings.TrimRight(os.Getenv("BASE_PATH"), "/")
+	if basePath != "" && !strings.HasPrefix(basePath, "/") {
+		basePath = "/" + basePath
+	}
+	for _, p := range strings.Split(os.Getenv("REPORT_PREFIXES"), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			reportPrefixes = append(reportPrefixes, p)
+		}
+	}
 	region := os.Getenv("AWS_REGION")
 	mongoURI = os.Getenv("DATABASE_URL")
 	redisURL = os.Getenv("REDIS_URL")
+	loadLimits()
+	statusInterval = time.Duration(envInt("STATUS_INTERVAL_SECONDS", 30)) * time.Second
+	statusStaleAfter = 3 * statusInterval
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
 	}
 
+	// S3 retries: adaptive mode backs off on throttling (503 SlowDown)
+	s3MaxAttempts := 5
+	if v, err := strconv.Atoi(os.Getenv("S3_MAX_ATTEMPTS")); err == nil && v > 0 {
+		s3MaxAttempts = v
+	}
+
+	// public-read buckets need no IAM setup: skip credential resolution and
+	// request signing entirely (presigned URLs become plain object URLs)
+	s3Anonymous := os.Getenv("S3_ANONYMOUS") == "true"
+	if s3Anonymous && region == "" {
+		region = "us-east-1"
+	}
+
 	// AWS Init
 	if region != "" {
-		cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion(region))
+		opts := []func(*config.LoadOptions) error{
+			config.WithRegion(region),
+			config.WithRetryMode(aws.RetryModeAdaptive),
+			config.WithRetryMaxAttempts(s3MaxAttempts),
+		}
+		if s3Anonymous {
+			opts = append(opts, config.WithCredentialsProvider(aws.AnonymousCredentials{}))
+		}
+		cfg, err := config.LoadDefaultConfig(context.TODO(), opts...)
 		if err == nil {
-			s3Client = s3.NewFromConfig(cfg)
-			s3Presign = s3.NewPresignClient(s3Client)
-			log.Println("AWS S3 initialized")
+			if cc, ok := cfg.Credentials.(*aws.CredentialsCache); ok {
+				s3Credentials = cc
+			}
+			setS3Client(s3.NewFromConfig(cfg))
+			if s3Anonymous {
+				slog.Info("AWS S3 initialized", "anonymous", true)
+			} else {
+				slog.Info("AWS S3 initialized")
+			}
 		} else {
-			log.Printf("AWS config error: %v", err)
+			slog.Error("AWS config failed", "backend", "s3", "error", err)
 		}
 	} else {
-		log.Println("AWS_REGION not set — S3 features disabled")
+		slog.Info("AWS_REGION not set — S3 features disabled")
+	}
+
+	if v := os.Getenv("SAMPLE_CACHE_TTL"); v != "" {
+		if ttl, err := time.ParseDuration(v); err == nil && ttl > 0 {
+			samples = newSampleCache(int(envInt("SAMPLE_CACHE_SIZE", 64)), ttl)
+			slog.Info("collection sample cache enabled", "ttl", ttl.String())
+		} else {
+			slog.Warn("ignoring invalid setting", "env", "SAMPLE_CACHE_TTL", "value", v)
+		}
 	}
 
 	// Mongo Init
 	if mongoURI != "" {
+		if cs, err := connstring.Parse(mongoURI); err == nil && cs.Database != "" {
+			mongoDefaultDB = cs.Database
+			slog.Info("mongo default database", "db", mongoDefaultDB)
+		}
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
-		client, err := mongo.Connect(ctx, options.Client().ApplyURI(mongoURI))
+		clientOpts := options.Client().ApplyURI(mongoURI).SetAppName(appName)
+		if os.Getenv("MONGO_MAX_POOL_SIZE") != "" {
+			clientOpts.SetMaxPoolSize(uint64(envInt("MONGO_MAX_POOL_SIZE", 100)))
+		}
+		// a client that can't reach the server yet is still installed: the
+		// driver keeps retrying, and until then /readyz reports mongo down
+		client, err := mongo.Connect(ctx, clientOpts)
 		if err != nil {
-			log.Printf("Mongo connect error: %v", err)
-		} else if err == nil && client.Ping(ctx, nil) == nil {
-			mongoClient = client
-			log.Println("Mongo connected")
+			slog.Error("mongo connect failed", "backend", "mongo", "error", err)
 		} else {
-			log.Printf("Mongo ping error: %v", err)
+			setMongoClient(client)
+			if err := client.Ping(ctx, nil); err != nil {
+				slog.Error("mongo ping failed", "backend", "mongo", "error", err)
+			} else {
+				slog.Info("mongo connected")
+			}
 		}
 	} else {
-		log.Println("DATABASE_URL not set — Mongo disabled")
+		slog.Info("DATABASE_URL not set — Mongo disabled")
 	}
 
 	// Redis Init
 	if redisURL != "" {
-		opt, err := redis.ParseURL(redisURL)
-		if err != nil {
-			opt = &redis.Options{Addr: redisURL}
+		// REDIS_CLUSTER=true, or several comma-separated addresses, connect
+		// to a Redis cluster
+		redisCluster = os.Getenv("REDIS_CLUSTER") == "true" || strings.Contains(redisURL, ",")
+		// REDIS_MAX_CONCURRENCY caps both the pool and in-flight commands, so
+		// many people browsing at once queue up instead of opening connections
+		var limiter redisLimiter
+		poolSize := 0
+		if os.Getenv("REDIS_MAX_CONCURRENCY") != "" {
+			poolSize = int(envInt("REDIS_MAX_CONCURRENCY", 10))
+			limiter = make(redisLimiter, poolSize)
 		}
-		rdb := redis.NewClient(opt)
-		if rdb.Ping(context.Background()).Err() == nil {
-			redisClient = rdb
-			log.Println("Redis connected")
+		rdb := newRedisClient(redisURL, poolSize)
+		if limiter != nil {
+			rdb.AddHook(limiter)
+		}
+		// installed even when the ping fails, like the Mongo client: it
+		// dials again on the next command
+		setRedisClient(rdb)
+		if err := rdb.Ping(context.Background()).Err(); err != nil {
+			slog.Error("redis ping failed", "backend", "redis", "error", err)
+		} else if redisCluster {
+			slog.Info("redis connected", "cluster", true)
 		} else {
-			log.Println("Redis ping failed")
+			slog.Info("redis connected")
 		}
 	} else {
-		log.Println("REDIS_URL not set — Redis disabled")
+		slog.Info("REDIS_URL not set — Redis disabled")
 	}
 
-	// routes
-	http.HandleFunc("/load-test", loadTestHandler)
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		// default redirect to load-test
-		http.Redirect(w, r, "/load-test", http.StatusFound)
-	})
-	http.HandleFunc("/db-data", dbDataHandler)
-	http.HandleFunc("/db-data/collection", dbCollectionHandler)
-	http.HandleFunc("/redis-data", redisDataHandler)
-	http.HandleFunc("/redis-data/key", redisKeyHandler)
+	// keep backend status fresh in the background for the sidebar and /readyz
+	startStatusLoop()
 
-	log.Printf("Server running on port %s...", port)
-	log.Fatal(http.ListenAndServe(":"+port, nil))
-}
+	// after BASE_PATH and branding are known, which the layout bakes in
+	parsePages()
 
-/////////////////////////////////////////////////////////////
-// S3 / Load test reports
-/////////////////////////////////////////////////////////////
+	registerMetrics()
+	mux := http.NewServeMux()
+	registerRoutes(mux)
 
-func loadTestHandler(w http.ResponseWriter, r *http.Request) {
-	if s3Client == nil || s3Presign == nil {
-		// render a friendly notice (so UI still loads)
-		content := `<div class="card"><h2>📊 Load Test Reports</h2><p style="color:#6b7280">S3 not configured or AWS credentials missing. Set <code>S3_BUCKET</code> and <code>AWS_REGION</code> or enable IRSA.</p></div>`
-		page := layout("Load Test Reports", content)
-		fmt.Fprint(w, page)
-		return
+	// routes are registered unprefixed; strip BASE_PATH before dispatching
+	var handler http.Handler = requireAuth(refreshOnNoCache(withDeadline(gzipResponses(instrument(mux)))))
+	if basePath != "" {
+		handler = http.StripPrefix(basePath, handler)
+		slog.Info("serving under base path", "base_path", basePath)
 	}
+	handler = accessLog(limitBody(handler))
 
-	reports, err := listReports(r.Context())
-	if err != nil {
-		http.Error(w, "Failed to list reports: "+err.Error(), 500)
-		return
+	// optional TLS; HTTP_REDIRECT_TO_HTTPS additionally answers plain HTTP
+	// on HTTP_PORT (default 80) with a redirect to the TLS port
+	servers := []server{{Server: &http.Server{Addr: ":" + port, Handler: handler}}}
+	certFile, keyFile := os.Getenv("TLS_CERT_FILE"), os.Getenv("TLS_KEY_FILE")
+	if certFile != "" && keyFile != "" {
+		servers[0].certFile, servers[0].keyFile = certFile, keyFile
+		if os.Getenv("HTTP_REDIRECT_TO_HTTPS") == "true" {
+			httpPort := os.Getenv("HTTP_PORT")
+			if httpPort == "" {
+				httpPort = "80"
+			}
+			servers = append(servers, server{Server: &http.Server{Addr: ":" + httpPort, Handler: httpsRedirect(port)}})
+			slog.Info("redirecting HTTP to HTTPS", "port", httpPort)
+		}
+		slog.Info("server running", "port", port, "tls", true)
+	} else {
+		slog.Info("server running", "port", port)
 	}
+	shutdownGrace = envDuration("SHUTDOWN_GRACE", shutdownGrace)
+	statusRefreshMin = envDuration("STATUS_REFRESH_MIN", statusRefreshMin)
+	runServers(servers)
+}
+
+// httpsRedirect redirects every request to the same URL over HTTPS on
+// tlsPort.
+func httpsRedirect(tlsPort string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if tlsPort != "443" {
+			host = net.JoinHostPort(host, tlsPort)
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusMovedPermanently)
+	})
+}
+
+/////////////////////////////////////////////////////////////
+// S3 / Load test reports
+/////////////////////////////////////////////////////////////
 
-	// prepare content template with template actions
-	content := `
+// reportsPage renders the report list.
+const reportsPage = `
 <div class="card">
-  <h2>📊 Load Test Reports</h2>
+  <h2>📊 Load Test Reports{{if .OtherBucket}} · {{.Bucket}}{{end}}{{if .Prefix}} — {{.Prefix}}{{end}}</h2>
+  {{if .Incomplete}}<p style="color:#b45309">{{.Incomplete}}</p>{{end}}
+  {{range .DateProblems}}<p style="color:#b45309">Ignoring {{.}}</p>{{end}}
 
   <div class="row">
-    <input id="reportSearch" class="search" placeholder="Filter reports..." onkeyup="filterList('reportSearch','rItem')"/>
+    <form method="get" style="flex:1;display:flex">
+      {{if .Meta}}<input type="hidden" name="meta" value="1"/>{{end}}
+      {{if .Tag}}<input type="hidden" name="tag" value="{{.Tag}}"/>{{end}}
+      {{if .CustomPerPage}}<input type="hidden" name="perPage" value="{{.PerPage}}"/>{{end}}
+      {{if and (gt (len .Buckets) 1) (not .Local)}}
+      <select name="bucket" onchange="if(this.form.prefix){this.form.prefix.value=''}this.form.submit()" style="margin-right:6px" title="Bucket">
+        {{range .Buckets}}<option value="{{.}}"{{if eq . $.Bucket}} selected{{end}}>{{.}}</option>{{end}}
+      </select>
+      {{end}}
+      {{if or .Prefixes .Prefix}}
+      <select name="prefix" onchange="this.form.submit()" style="margin-right:6px">
+        <option value="">All prefixes</option>
+        {{range .Prefixes}}<option value="{{.}}"{{if eq . $.Prefix}} selected{{end}}>{{.}}</option>{{end}}
+        {{if and .Prefix (not .PrefixListed)}}<option value="{{.Prefix}}" selected>{{.Prefix}}</option>{{end}}
+      </select>
+      {{end}}
+      <input id="reportSearch" name="q" value="{{.Q}}" class="search" placeholder="Filter reports... (Enter to search server-side)" onkeyup="filterList('reportSearch','rItem')"/>
+      <input type="date" name="from" value="{{.From}}" title="Modified on or after" onchange="this.form.submit()" style="margin-left:6px"/>
+      <input type="date" name="to" value="{{.To}}" title="Modified on or before" onchange="this.form.submit()" style="margin-left:6px"/>
+    </form>
+    <a href="/load-test/activity" style="white-space:nowrap">📅 Activity</a>
+    {{if not .Local}}<form method="post" action="/load-test/refresh" style="margin:0">
+      <input type="hidden" name="csrf" value="{{.CSRF}}"/><input type="hidden" name="back" value="{{.Back}}"/>
+      <button class="copy-btn" type="submit" style="white-space:nowrap" title="Drop cached report links and tags, e.g. after overwriting a report">↻ Purge cache</button>
+    </form>{{end}}
+    {{if not .Local}}{{if .Meta}}<a href="/load-test?meta=0{{if .OtherBucket}}&bucket={{.Bucket}}{{end}}" style="white-space:nowrap">Hide metadata</a>{{else}}<a href="/load-test?meta=1{{if .OtherBucket}}&bucket={{.Bucket}}{{end}}" style="white-space:nowrap">Show metadata</a>{{end}}{{end}}
   </div>
 
+  {{if .Tag}}<div class="chips"><span class="chip">tag {{.Tag}}</span> <a href="/load-test?q={{.Q}}{{if .OtherBucket}}&bucket={{.Bucket}}{{end}}">clear</a></div>{{end}}
+
   <div class="list">
-  {{range .}}
+  {{range .Reports}}
+    {{if .RunStart}}<div style="margin:12px 0 4px;color:#6b7280;font-size:13px;font-weight:600">📁 {{if .Run}}{{.Run}}{{else}}/{{end}}</div>{{end}}
     <div class="list-item rItem">
-      <div><a href="{{.URL}}" target="_blank">{{.Name}}</a></div>
-      <div class="badge">{{.Date}}</div>
+      <div>
+        {{if $.Grouped}}<span class="chip" style="margin-right:6px">{{.Kind}}</span>{{end}}
+        {{if .PreviewURL}}<a href="{{.PreviewURL}}">{{highlight .Name $.Q}}</a>{{else}}<a href="{{.URL}}" target="_blank">{{highlight .Name $.Q}}</a>{{end}}
+        {{if .DownloadURL}}<a href="{{.DownloadURL}}" title="Download" style="margin-left:6px">⬇</a>{{end}}
+        {{if not $.Local}}<a href="/load-test/share?key={{.Name}}{{if $.OtherBucket}}&bucket={{$.Bucket}}{{end}}" title="Share link" style="margin-left:6px">🔗</a>{{end}}
+        {{if .Public}}<span class="chip" style="background:#fee2e2;color:#b91c1c;margin-left:6px" title="The object ACL grants read access to everyone">⚠ public</span>{{end}}
+        {{if .Metadata}}<div class="chips">{{range $k, $v := .Metadata}}<span class="chip">{{$k}}: {{$v}}</span>{{end}}</div>{{end}}
+      </div>
+      <div style="white-space:nowrap">
+        {{if .ContentType}}<span style="color:#6b7280;font-size:12px;margin-right:6px">{{bytes .Size}} · {{.ContentType}}</span>{{end}}
+        <span class="badge">{{.Date}}</span>
+      </div>
     </div>
   {{end}}
   </div>
+
+  {{if gt .Pages 1}}
+  <div class="row" style="justify-content:center;margin-top:12px">
+    {{if gt .Page 1}}<a href="{{.PrevURL}}">← Prev</a>{{end}}
+    <span style="color:#6b7280">Page {{.Page}} of {{.Pages}} · {{.Total}} reports</span>
+    {{if lt .Page .Pages}}<a href="{{.NextURL}}">Next →</a>{{end}}
+  </div>
+  {{end}}
 </div>
 `
-	tpl := template.Must(template.New("reports").Parse(layout("Load Test Reports", content)))
-	tpl.Execute(w, reports)
-}
 
-func listReports(ctx context.Context) ([]SimpleReportView, error) {
-	resp, err := s3Client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
-		Bucket: aws.String(s3Bucket),
-	})
-	if err != nil {
-		return nil, err
+func loadTestHandler(w http.ResponseWriter, r *http.Request) {
+	local := localReports()
+	if !local && (s3Client() == nil || s3Presign() == nil) {
+		// render a friendly notice (so UI still loads)
+		content := `<div class="card"><h2>📊 Load Test Reports</h2><p style="color:#6b7280">S3 not configured or AWS credentials missing. Set <code>S3_BUCKET</code> and <code>AWS_REGION</code> or enable IRSA, or set <code>REPORTS_DIR</code> to read reports from disk.</p></div>`
+		page := layout("Load Test Reports", content, backendStatus())
+		fmt.Fprint(w, page)
+		return
 	}
-	var items []Report
-	for _, obj := range resp.Contents {
-		if strings.HasSuffix(*obj.Key, ".html") {
-			ps, err := s3Presign.PresignGetObject(ctx, &s3.GetObjectInput{
-				Bucket: aws.String(s3Bucket),
-				Key:    obj.Key,
-			}, s3.WithPresignExpires(24*time.Hour))
-			if err != nil {
-				log.Printf("presign error %v", err)
-				continue
-			}
-			items = append(items, Report{
-				Name: *obj.Key,
-				URL:  ps.URL,
-				Date: aws.ToTime(obj.LastModified),
-			})
-		}
+
+	// S3 user-metadata costs a HeadObject per report, so it's opt-in
+	withMeta := reportMetadata
+	if v := r.URL.Query().Get("meta"); v != "" {
+		withMeta = v == "1"
 	}
 
-	// sort latest first
-	sort.Slice(items, func(i, j int) bool { return items[i].Date.After(items[j].Date) })
+	// ?prefix= scopes the listing to one "folder", e.g. checkout/
+	prefix := r.URL.Query().Get("prefix")
 
-	var out []SimpleReportView
-	for _, r := range items {
-		out = append(out, SimpleReportView{
-			Name: r.Name,
-			URL:  r.URL,
-			Date: r.Date.Format("2006-01-02 15:04"),
-		})
+	// ?bucket= picks one of S3_BUCKETS
+	bucket, ok := requestBucket(r)
+	if !ok {
+		http.Error(w, "bucket not allowed", 400)
+		return
 	}
-	return out, nil
-}
 
-/////////////////////////////////////////////////////////////
-// Mongo viewer
-/////////////////////////////////////////////////////////////
+	// ?from= and ?to= bound LastModified; a bad date is ignored with a
+	// notice. Applied while listing, before anything is presigned.
+	span, dateProblems := requestReportRange(r)
 
-func dbDataHandler(w http.ResponseWriter, r *http.Request) {
-	if mongoClient == nil {
-		content := `<div class="card"><h2>MongoDB Collections</h2><p style="color:#6b7280">MongoDB not configured or unreachable. Set DATABASE_URL or check network access.</p></div>`
-		page := layout("MongoDB Collections", content)
-		fmt.Fprint(w, page)
+	// a listing that failed part-way still shows what was found, with a banner
+	var reports []SimpleReportView
+	var prefixes []string
+	var err error
+	if local {
+		withMeta = false
+		reports, err = listLocalReports()
+		inRange := reports[:0]
+		for _, rep := range reports {
+			if span.contains(rep.LastModified) {
+				inRange = append(inRange, rep)
+			}
+		}
+		reports = inRange
+	} else {
+		reports, err = listReports(r.Context(), bucket, prefix, span)
+		var perr error
+		if prefixes, perr = topPrefixes(r.Context(), bucket); perr != nil {
+			slog.Error("list report prefixes failed", "backend", "s3", "bucket", bucket, "error", perr)
+		}
+	}
+	if err != nil && len(reports) == 0 {
+		if isAWSAuthError(err) {
+			renderCredsExpired(w, "📊 Load Test Reports", err)
+			return
+		}
+		http.Error(w, "Failed to list reports: "+err.Error(), 500)
 		return
 	}
+	incomplete := ""
+	if err != nil {
+		slog.Error("report listing incomplete", "backend", "s3", "bucket", bucket, "error", err)
+		incomplete = fmt.Sprintf("Listing incomplete — showing the %d reports found before an error: %v", len(reports), err)
+	}
 
-	ctx := context.Background()
-	dbs, err := mongoClient.ListDatabaseNames(ctx, bson.M{})
-	if err != nil || len(dbs) == 0 {
-		content := `<div class="card"><h2>MongoDB Collections</h2><p style="color:#6b7280">No databases found or failed to list databases.</p></div>`
-		page := layout("MongoDB Collections", content)
-		fmt.Fprint(w, page)
-		return
+	q := r.URL.Query().Get("q")
+	if q != "" {
+		matched := reports[:0]
+		for _, rep := range reports {
+			if matchesQuery(rep.Name, q) {
+				matched = append(matched, rep)
+			}
+		}
+		reports = matched
 	}
 
-	// pick first non-system DB
-	var dbName string
-	for _, d := range dbs {
-		if d != "admin" && d != "local" && d != "config" {
-			dbName = d
-			break
+	tag := r.URL.Query().Get("tag")
+	if tag != "" && !local {
+		tk, tv, ok := strings.Cut(tag, ":")
+		if !ok {
+			http.Error(w, "tag must be key:value", 400)
+			return
 		}
+		reports = filterByTag(r.Context(), bucket, reports, tk, tv, noCache(r))
 	}
-	if dbName == "" {
-		dbName = dbs[0]
+
+	// keep a run's artifacts together across pages
+	grouped := groupedReports()
+	if grouped {
+		groupByRun(reports)
 	}
 
-	cols, err := mongoClient.Database(dbName).ListCollectionNames(ctx, bson.M{})
-	if err != nil {
-		content := `<div class="card"><h2>MongoDB Collections</h2><p style="color:#6b7280">Failed to list collections: ` + template.HTMLEscapeString(err.Error()) + `</p></div>`
-		page := layout("MongoDB Collections", content)
-		fmt.Fprint(w, page)
-		return
+	// page through the sorted, filtered set; the per-report S3 calls below
+	// only run for the visible page
+	total := len(reports)
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("perPage"))
+	if perPage < 1 {
+		perPage = reportsPerPage
+	}
+	if perPage > maxReportsPerPage {
+		perPage = maxReportsPerPage
+	}
+	pages := (total + perPage - 1) / perPage
+	if pages == 0 {
+		pages = 1
+	}
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	if page > pages {
+		page = pages
+	}
+	lo, hi := (page-1)*perPage, page*perPage
+	if hi > total {
+		hi = total
+	}
+	reports = reports[lo:hi]
+	if grouped {
+		markRunStarts(reports)
+	}
+	pageURL := func(p int) string {
+		pq := r.URL.Query()
+		pq.Set("page", strconv.Itoa(p))
+		pq.Del("nocache")
+		// built at request time, so withBasePath can't see it
+		return basePath + "/load-test?" + pq.Encode()
 	}
 
-	// build ColView slice with counts (estimated)
-	var colViews []ColView
-	for _, c := range cols {
-		cnt, _ := mongoClient.Database(dbName).Collection(c).EstimatedDocumentCount(ctx)
-		colViews = append(colViews, ColView{
-			Name:     c,
-			RowCount: cnt,
-		})
+	if !local {
+		reports = signReports(r.Context(), bucket, reports, presignExpiryFor(r), noCache(r))
 	}
 
-	// content template with Go template actions
-	content := `
-<div class="card">
-  <h2>📦 MongoDB Collections ({{.DB}})</h2>
-  <div class="row">
-    <input id="mongoSearch" class="search" placeholder="Filter collections..." onkeyup="filterList('mongoSearch','mItem')"/>
-  </div>
+	if (withMeta || reportEnrich) && !local {
+		enrichReports(r.Context(), bucket, reports, withMeta, reportEnrich)
+	}
 
-  <div class="list">
-    {{range .Cols}}
-      <div class="list-item mItem">
-        <div><a href="/db-data/collection?name={{.Name}}">{{.Name}}</a></div>
-        <div class="badge">{{.RowCount}}</div>
-      </div>
-    {{end}}
-  </div>
-</div>
-`
+	// flag reports uploaded with a public-read ACL
+	checkACL := reportACLCheck
+	if v := r.URL.Query().Get("acl"); v != "" {
+		checkACL = v == "1"
+	}
+	if checkACL && !local {
+		markPublicReports(r.Context(), bucket, reports)
+	}
 
-	tpl := template.Must(template.New("db").Parse(layout("MongoDB Collections", content)))
-	tpl.Execute(w, map[string]interface{}{
-		"DB":   dbName,
-		"Cols": colViews,
+	renderPage(w, "reports", map[string]interface{}{
+		"Reports":       reports,
+		"Meta":          withMeta,
+		"Q":             q,
+		"Tag":           tag,
+		"Incomplete":    incomplete,
+		"DateProblems":  dateProblems,
+		"From":          dateInputValue(r.URL.Query().Get("from")),
+		"To":            dateInputValue(r.URL.Query().Get("to")),
+		"Local":         local,
+		"Grouped":       grouped,
+		"CSRF":          csrfToken,
+		"Back":          "/load-test?" + r.URL.RawQuery,
+		"Page":          page,
+		"Pages":         pages,
+		"Total":         total,
+		"PerPage":       perPage,
+		"CustomPerPage": perPage != reportsPerPage,
+		"Prefix":        prefix,
+		"Prefixes":      prefixes,
+		"PrefixListed":  slices.Contains(prefixes, prefix),
+		"Bucket":        bucket,
+		"Buckets":       s3Buckets,
+		"OtherBucket":   bucket != s3Bucket,
+		"PrevURL":       pageURL(page - 1),
+		"NextURL":       pageURL(page + 1),
 	})
 }
 
-func dbCollectionHandler(w http.ResponseWriter, r *http.Request) {
-	if mongoClient == nil {
-		content := `<div class="card"><h2>Collection</h2><p style="color:#6b7280">Mongo not configured.</p></div>`
-		page := layout("Collection", content)
-		fmt.Fprint(w, page)
-		return
+// listReports returns the report views of bucket modified within span for
+// the index page, latest first and not yet presigned: callers filter and
+// page the list, then sign what they show with signReports. Like
+// scanReports, a listing that fails part-way returns what was found so far
+// together with the error.
+func listReports(ctx context.Context, bucket, prefix string, span reportRange) ([]SimpleReportView, error) {
+	if !allowedBucket(bucket) {
+		return nil, fmt.Errorf("bucket %q not allowed", bucket)
 	}
+	items, listErr := scanReports(ctx, bucket, prefix, time.Time{})
 
-	name := r.URL.Query().Get("name")
+	var out []SimpleReportView
+	for _, r := range items {
+		if !span.contains(r.Date) {
+			continue
+		}
+		view := SimpleReportView{
+			Name:         r.Name,
+			Date:         r.Date.Format("2006-01-02 15:04"),
+			LastModified: r.Date,
+			Kind:         reportKind(r.Name),
+			Run:          reportRun(r.Name),
+		}
+		if previewKinds[view.Kind] {
+			view.PreviewURL = previewURL(bucket, r.Name)
+		}
+		out = append(out, view)
+	}
+	reportsListed.Set(float64(len(out)))
+	return out, listErr
+}
+
+// signReports presigns the open and download links of reports, which should
+// be only the ones about to be shown, bypassing reportURLs when fresh.
+// Reports that can't be presigned are dropped, as in fetchReports.
+func signReports(ctx context.Context, bucket string, reports []SimpleReportView, expires time.Duration, fresh bool) []SimpleReportView {
+	out := reports[:0]
+	for _, r := range reports {
+		u, err := cachedPresign(ctx, bucket, r.Name, "", expires, r.LastModified, fresh)
+		if err != nil {
+			slog.Error("presign failed", "backend", "s3", "key", r.Name, "error", err)
+			continue
+		}
+		r.URL = u
+		if u, err := cachedPresign(ctx, bucket, r.Name, "1", expires, r.LastModified, fresh); err == nil {
+			r.DownloadURL = u
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// narrowPrefixes intersects the configured prefixes with prefix: a
+// configured prefix under prefix is kept, and prefix itself is used when it
+// lies under a configured one. Without configured prefixes it's just prefix.
+func narrowPrefixes(configured []string, prefix string) []string {
+	if len(configured) == 0 {
+		return []string{prefix}
+	}
+	var out []string
+	for _, p := range configured {
+		switch {
+		case strings.HasPrefix(prefix, p):
+			return []string{prefix}
+		case strings.HasPrefix(p, prefix):
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// topPrefixes lists the top-level "folders" of bucket, for the prefix
+// picker on the report list.
+func topPrefixes(ctx context.Context, bucket string) ([]string, error) {
+	var out []string
+	pages := s3.NewListObjectsV2Paginator(s3Client(), &s3.ListObjectsV2Input{
+		Bucket:    aws.String(bucket),
+		Delimiter: aws.String("/"),
+	})
+	for pages.HasMorePages() {
+		resp, err := pages.NextPage(ctx)
+		if err != nil {
+			return out, backendErr("s3", err)
+		}
+		for _, cp := range resp.CommonPrefixes {
+			out = append(out, aws.ToString(cp.Prefix))
+		}
+	}
+	return out, nil
+}
+
+// presigned report links last presignExpiry (PRESIGN_EXPIRY) unless a
+// request asks for another ?expiry=, which may not exceed presignMaxExpiry
+// (PRESIGN_MAX_EXPIRY). SigV4 presigns can't outlive 7 days.
+var (
+	presignExpiry    = 24 * time.Hour
+	presignMaxExpiry = 7 * 24 * time.Hour
+)
+
+// loadPresignExpiry reads PRESIGN_EXPIRY and PRESIGN_MAX_EXPIRY, keeping
+// the defaults for invalid values.
+func loadPresignExpiry() {
+	presignMaxExpiry = envDuration("PRESIGN_MAX_EXPIRY", presignMaxExpiry)
+	if presignMaxExpiry > 7*24*time.Hour {
+		presignMaxExpiry = 7 * 24 * time.Hour
+	}
+	presignExpiry = envDuration("PRESIGN_EXPIRY", presignExpiry)
+	if presignExpiry > presignMaxExpiry {
+		presignExpiry = presignMaxExpiry
+	}
+}
+
+// presignExpiryFor returns the link lifetime for a request: ?expiry= (a
+// Go duration) capped at presignMaxExpiry, or the default when absent or
+// invalid.
+func presignExpiryFor(r *http.Request) time.Duration {
+	raw := r.URL.Query().Get("expiry")
+	if raw == "" {
+		return presignExpiry
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		slog.Warn("ignoring invalid ?expiry=", "value", raw, "default", presignExpiry.String())
+		return presignExpiry
+	}
+	if d > presignMaxExpiry {
+		slog.Warn("?expiry= exceeds the maximum, capping", "value", d.String(), "max", presignMaxExpiry.String())
+		return presignMaxExpiry
+	}
+	return d
+}
+
+// report list page sizes (?perPage=)
+const (
+	reportsPerPage    = 50
+	maxReportsPerPage = 1000
+)
+
+// enrichReports HeadObjects the reports concurrently (bounded) and merges
+// their user-metadata when meta is set and size and content type when full
+// is set. Failed HEADs are logged and leave the report as listed.
+func enrichReports(ctx context.Context, bucket string, reports []SimpleReportView, meta, full bool) {
+	g := new(errgroup.Group)
+	g.SetLimit(8)
+	for i, rep := range reports {
+		g.Go(func() error {
+			head, err := s3Client().HeadObject(ctx, &s3.HeadObjectInput{
+				Bucket: aws.String(bucket),
+				Key:    aws.String(rep.Name),
+			})
+			countBackendError("s3", err)
+			if err != nil {
+				slog.Error("head object failed", "backend", "s3", "key", rep.Name, "error", err)
+				return nil
+			}
+			if meta {
+				reports[i].Metadata = head.Metadata
+			}
+			if full {
+				reports[i].Size = aws.ToInt64(head.ContentLength)
+				reports[i].ContentType = aws.ToString(head.ContentType)
+			}
+			return nil
+		})
+	}
+	g.Wait()
+}
+
+// filterByTag keeps the reports whose S3 object tags include key=value.
+// Tags are looked up concurrently and cached (unless fresh); lookup errors
+// drop the report.
+func filterByTag(ctx context.Context, bucket string, reports []SimpleReportView, key, value string, fresh bool) []SimpleReportView {
+	keep := make([]bool, len(reports))
+	g := new(errgroup.Group)
+	g.SetLimit(8)
+	for i, rep := range reports {
+		g.Go(func() error {
+			tags, err := reportObjectTags(ctx, bucket, rep, fresh)
+			if err != nil {
+				slog.Error("get tagging failed", "backend", "s3", "key", rep.Name, "error", err)
+				return nil
+			}
+			if v, ok := tags[key]; ok && v == value {
+				keep[i] = true
+			}
+			return nil
+		})
+	}
+	g.Wait()
+
+	out := reports[:0]
+	for i, rep := range reports {
+		if keep[i] {
+			out = append(out, rep)
+		}
+	}
+	return out
+}
+
+// reportObjectTags returns the S3 object tags of a report, from reportTags
+// when cached unless fresh is set.
+func reportObjectTags(ctx context.Context, bucket string, rep SimpleReportView, fresh bool) (map[string]string, error) {
+	cacheKey := bucket + "\x00" + rep.Name + "\x00" + rep.Date
+	if tags, ok := reportTags.get(cacheKey); ok && !fresh {
+		return tags, nil
+	}
+	out, err := s3Client().GetObjectTagging(ctx, &s3.GetObjectTaggingInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(rep.Name),
+	})
+	if err != nil {
+		return nil, backendErr("s3", err)
+	}
+	tags := make(map[string]string, len(out.TagSet))
+	for _, t := range out.TagSet {
+		tags[aws.ToString(t.Key)] = aws.ToString(t.Value)
+	}
+	reportTags.put(cacheKey, tags)
+	return tags, nil
+}
+
+// fetchReports lists the reports of bucket modified after since (zero =
+// all), presigns them for expires (skipping reportURLs when fresh) and
+// returns them latest first. Partial listings are returned with their error,
+// as in scanReports.
+func fetchReports(ctx context.Context, bucket, prefix string, since time.Time, expires time.Duration, fresh bool) ([]Report, error) {
+	all, listErr := scanReports(ctx, bucket, prefix, since)
+	items := all[:0]
+	for _, r := range all {
+		u, err := cachedPresign(ctx, bucket, r.Name, "", expires, r.Date, fresh)
+		if err != nil {
+			slog.Error("presign failed", "backend", "s3", "key", r.Name, "error", err)
+			continue
+		}
+		r.URL = u
+		items = append(items, r)
+	}
+	return items, listErr
+}
+
+// presignReportFor presigns a GET for key valid for expires. download
+// overrides how the browser handles the object: "1" forces a save as the
+// key's base name, "0" forces inline display typed by extension, "" keeps
+// the stored headers.
+func presignReportFor(ctx context.Context, bucket, key, download string, expires time.Duration) (string, error) {
+	in := &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}
+	switch download {
+	case "1":
+		in.ResponseContentDisposition = aws.String(mime.FormatMediaType("attachment", map[string]string{"filename": path.Base(key)}))
+		in.ResponseContentType = aws.String("application/octet-stream")
+	case "0":
+		in.ResponseContentDisposition = aws.String("inline")
+		if ct := mime.TypeByExtension(path.Ext(key)); ct != "" {
+			in.ResponseContentType = aws.String(ct)
+		}
+	}
+	ps, err := s3Presign().PresignGetObject(ctx, in, s3.WithPresignExpires(expires))
+	if err != nil {
+		return "", backendErr("s3", err)
+	}
+	return ps.URL, nil
+}
+
+// cachedPresign is presignReportFor through reportURLs, for listings that
+// re-sign every report on each load. modified is the object's LastModified;
+// an overwritten report gets a new URL. fresh (?nocache=1) always re-signs
+// and replaces the cached URL.
+func cachedPresign(ctx context.Context, bucket, key, download string, expires time.Duration, modified time.Time, fresh bool) (string, error) {
+	cacheKey := bucket + "\x00" + key + "\x00" + download + "\x00" + expires.String()
+	if u, ok := reportURLs.get(cacheKey, modified); ok && !fresh {
+		return u, nil
+	}
+	u, err := presignReportFor(ctx, bucket, key, download, expires)
+	if err != nil {
+		return "", err
+	}
+	reportURLs.put(cacheKey, u, modified, expires)
+	return u, nil
+}
+
+// scanReports lists the reports (see reportExtensions) in bucket modified after since,
+// latest first, without presigning them.
+//
+// If listing fails part-way, the reports found so far are returned along
+// with the error.
+func scanReports(ctx context.Context, bucket, prefix string, since time.Time) ([]Report, error) {
+	objects, err := listReportObjects(ctx, bucket, prefix)
+	var items []Report
+	for _, obj := range objects {
+		if !isReportKey(*obj.Key) {
+			continue
+		}
+		modified := aws.ToTime(obj.LastModified)
+		if !modified.After(since) {
+			continue
+		}
+		items = append(items, Report{
+			Name:   *obj.Key,
+			Date:   modified,
+			Size:   aws.ToInt64(obj.Size),
+			Bucket: bucket,
+		})
+	}
+
+	// sort latest first
+	sort.Slice(items, func(i, j int) bool { return items[i].Date.After(items[j].Date) })
+	return items, err
+}
+
+// listReportObjects lists the bucket, or when REPORT_PREFIXES is set, each
+// configured prefix in parallel. Overlapping prefixes are de-duplicated.
+// A failing prefix doesn't stop the others; whatever was listed is returned
+// with the first error. A non-empty prefix (?prefix=) narrows the listing
+// further (see narrowPrefixes).
+func listReportObjects(ctx context.Context, bucket, prefix string) ([]types.Object, error) {
+	prefixes := reportPrefixes
+	if prefix != "" {
+		if prefixes = narrowPrefixes(reportPrefixes, prefix); len(prefixes) == 0 {
+			return nil, nil
+		}
+	}
+	if len(prefixes) == 0 {
+		return listObjects(ctx, bucket, "")
+	}
+
+	results := make([][]types.Object, len(prefixes))
+	g := new(errgroup.Group)
+	for i, prefix := range prefixes {
+		g.Go(func() error {
+			objs, err := listObjects(ctx, bucket, prefix)
+			results[i] = objs
+			if err != nil {
+				return fmt.Errorf("prefix %q: %w", prefix, err)
+			}
+			return nil
+		})
+	}
+	err := g.Wait()
+
+	seen := make(map[string]bool)
+	var out []types.Object
+	for _, objs := range results {
+		for _, obj := range objs {
+			if !seen[*obj.Key] {
+				seen[*obj.Key] = true
+				out = append(out, obj)
+			}
+		}
+	}
+	return out, err
+}
+
+// listObjects lists every object under prefix, one ListObjectsV2 page at a
+// time. If a page fails, the objects from the earlier pages are returned
+// with the error.
+func listObjects(ctx context.Context, bucket, prefix string) ([]types.Object, error) {
+	in := &s3.ListObjectsV2Input{
+		Bucket:  aws.String(bucket),
+		MaxKeys: aws.Int32(limits.ReportPageSize),
+	}
+	if prefix != "" {
+		in.Prefix = aws.String(prefix)
+	}
+	var out []types.Object
+	pages := s3.NewListObjectsV2Paginator(s3Client(), in)
+	for page := 1; pages.HasMorePages(); page++ {
+		resp, err := pages.NextPage(ctx)
+		if err != nil {
+			return out, fmt.Errorf("listing page %d: %w", page, backendErr("s3", err))
+		}
+		out = append(out, resp.Contents...)
+	}
+	return out, nil
+}
+
+// recentReportsHandler returns reports uploaded after ?since= (RFC3339) as
+// JSON, so CI can poll for a freshly uploaded report and grab its link.
+func recentReportsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if s3Client() == nil || s3Presign() == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]string{"error": "s3 not configured"})
+		return
+	}
+
+	since, err := time.Parse(time.RFC3339, r.URL.Query().Get("since"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "since must be an RFC3339 timestamp"})
+		return
+	}
+
+	reports, err := fetchReports(r.Context(), s3Bucket, "", since, presignExpiryFor(r), noCache(r))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	if reports == nil {
+		reports = []Report{}
+	}
+	json.NewEncoder(w).Encode(reports)
+}
+
+// apiLoadTestHandler returns the report list of /load-test as JSON, latest
+// first: ?bucket=, ?prefix=, ?from= and ?to= as on the page, ?limit= caps
+// the count.
+func apiLoadTestHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if s3Client() == nil || s3Presign() == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]string{"error": "s3 not configured"})
+		return
+	}
+
+	limit := 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "limit must be a positive integer"})
+			return
+		}
+		limit = n
+	}
+	bucket, ok := requestBucket(r)
+	if !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "bucket not allowed"})
+		return
+	}
+
+	span, problems := requestReportRange(r)
+	if len(problems) > 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": strings.Join(problems, "; ")})
+		return
+	}
+
+	reports, err := listReports(r.Context(), bucket, r.URL.Query().Get("prefix"), span)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	if limit > 0 && len(reports) > limit {
+		reports = reports[:limit]
+	}
+	reports = signReports(r.Context(), bucket, reports, presignExpiryFor(r), noCache(r))
+	if reports == nil {
+		reports = []SimpleReportView{}
+	}
+	json.NewEncoder(w).Encode(reports)
+}
+
+// reportOpenHandler redirects to a freshly presigned URL for key, giving
+// links that never expire even though each presign does. ?download=1 makes
+// the browser save the report, ?download=0 opens it inline.
+func reportOpenHandler(w http.ResponseWriter, r *http.Request) {
+	if s3Client() == nil || s3Presign() == nil {
+		http.Error(w, "S3 not configured", 503)
+		return
+	}
+
+	key := r.URL.Query().Get("key")
+	if key == "" {
+		http.Error(w, "missing key param", 400)
+		return
+	}
+	bucket, ok := requestBucket(r)
+	if !ok {
+		http.Error(w, "bucket not allowed", 400)
+		return
+	}
+
+	u, err := presignReportFor(r.Context(), bucket, key, r.URL.Query().Get("download"), presignExpiryFor(r))
+	if err != nil {
+		http.Error(w, "Failed to presign report: "+err.Error(), 500)
+		return
+	}
+	http.Redirect(w, r, u, http.StatusFound)
+}
+
+// reportIndexHandler exports every report as JSON or CSV (?format=csv) for
+// scripting and archival. Links point at /load-test/open rather than
+// presigned URLs so the manifest stays valid.
+func reportIndexHandler(w http.ResponseWriter, r *http.Request) {
+	if s3Client() == nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]string{"error": "s3 not configured"})
+		return
+	}
+
+	reports, err := scanReports(r.Context(), s3Bucket, "", time.Time{})
+	if err != nil {
+		http.Error(w, "Failed to list reports: "+err.Error(), 500)
+		return
+	}
+
+	base := requestBaseURL(r)
+	for i := range reports {
+		reports[i].URL = base + basePath + "/load-test/open?key=" + url.QueryEscape(reports[i].Name)
+	}
+
+	switch r.URL.Query().Get("format") {
+	case "", "json":
+		w.Header().Set("Content-Type", "application/json")
+		if reports == nil {
+			reports = []Report{}
+		}
+		json.NewEncoder(w).Encode(reports)
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", `attachment; filename="report-index.csv"`)
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"name", "url", "lastModified", "size"})
+		for _, rep := range reports {
+			cw.Write([]string{rep.Name, rep.URL, rep.Date.UTC().Format(time.RFC3339), strconv.FormatInt(rep.Size, 10)})
+		}
+		cw.Flush()
+	default:
+		http.Error(w, "format must be json or csv", 400)
+	}
+}
+
+// requestBucket returns the ?bucket= of r, or s3Bucket without one. ok is
+// false when the bucket isn't one of s3Buckets.
+func requestBucket(r *http.Request) (bucket string, ok bool) {
+	b := r.URL.Query().Get("bucket")
+	if b == "" {
+		return s3Bucket, true
+	}
+	return b, allowedBucket(b)
+}
+
+// allowedBucket reports whether b is one of the configured buckets, so
+// request parameters can't point the viewer at arbitrary buckets.
+func allowedBucket(b string) bool {
+	for _, c := range s3Buckets {
+		if c == b {
+			return true
+		}
+	}
+	return false
+}
+
+// search bounds: one slow bucket must not hold up the rest
+const (
+	searchConcurrency   = 4
+	searchBucketTimeout = 10 * time.Second
+)
+
+// searchReports matches q against report names in every configured bucket
+// concurrently, returning the matches latest first and the buckets that
+// failed or timed out.
+func searchReports(ctx context.Context, q string) ([]Report, []string) {
+	var (
+		mu      sync.Mutex
+		results []Report
+		failed  []string
+	)
+	g := new(errgroup.Group)
+	g.SetLimit(searchConcurrency)
+	for _, bucket := range s3Buckets {
+		g.Go(func() error {
+			ctx, cancel := context.WithTimeout(ctx, searchBucketTimeout)
+			defer cancel()
+			reports, err := scanReports(ctx, bucket, "", time.Time{})
+
+			// a partially listed bucket still contributes its matches
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				slog.Error("search bucket failed", "backend", "s3", "bucket", bucket, "error", err)
+				failed = append(failed, bucket)
+			}
+			for _, rep := range reports {
+				if matchesQuery(rep.Name, q) {
+					results = append(results, rep)
+				}
+			}
+			return nil
+		})
+	}
+	g.Wait()
+	sort.Slice(results, func(i, j int) bool { return results[i].Date.After(results[j].Date) })
+	return results, failed
+}
+
+// searchPage renders report search across buckets.
+const searchPage = `
+<div class="card">
+  <h2>🔎 Report Search</h2>
+  <form method="get" class="row">
+    <input name="q" value="{{.Q}}" class="search" placeholder="Search report names across all buckets..."/>
+    <button class="copy-btn" type="submit">Search</button>
+  </form>
+  {{if .Failed}}<p style="color:#b45309">Could not search: {{range $i, $b := .Failed}}{{if $i}}, {{end}}{{$b}}{{end}}</p>{{end}}
+  {{if .Q}}<p style="color:#6b7280">{{len .Results}} matches</p>{{end}}
+
+  <div class="list">
+  {{range .Results}}
+    <div class="list-item">
+      <div><a href="/load-test/open?bucket={{.Bucket}}&key={{.Name}}" target="_blank">{{highlight .Name $.Q}}</a></div>
+      <div style="white-space:nowrap"><span class="chip">{{.Bucket}}</span> <span class="badge">{{.Date.Format "2006-01-02 15:04"}}</span></div>
+    </div>
+  {{end}}
+  </div>
+</div>
+`
+
+// reportSearchHandler searches report names across all configured buckets
+// concurrently and renders the merged matches, latest first, labeled with
+// the bucket they came from. Buckets that fail or time out are listed.
+func reportSearchHandler(w http.ResponseWriter, r *http.Request) {
+	if s3Client() == nil {
+		content := `<div class="card"><h2>🔎 Report Search</h2><p style="color:#6b7280">S3 not configured.</p></div>`
+		page := layout("Report Search", content, backendStatus())
+		fmt.Fprint(w, page)
+		return
+	}
+
+	q := r.URL.Query().Get("q")
+	var results []Report
+	var failed []string
+	if q != "" {
+		results, failed = searchReports(r.Context(), q)
+	}
+
+	renderPage(w, "search", map[string]interface{}{
+		"Q":       q,
+		"Results": results,
+		"Failed":  failed,
+	})
+}
+
+// requestBaseURL returns scheme://host of the request, honoring
+// X-Forwarded-Proto when running behind a TLS-terminating proxy.
+func requestBaseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if p := r.Header.Get("X-Forwarded-Proto"); p != "" {
+		scheme = p
+	}
+	return scheme + "://" + r.Host
+}
+
+// reportProxyHandler streams a report through the server rather than
+// handing out a presigned URL. Objects stored with Content-Encoding: gzip are
+// passed through when the client accepts gzip and decompressed otherwise.
+func reportProxyHandler(w http.ResponseWriter, r *http.Request) {
+	if s3Client() == nil {
+		http.Error(w, "S3 not configured", 503)
+		return
+	}
+
+	key := r.URL.Query().Get("key")
+	if key == "" {
+		http.Error(w, "missing key param", 400)
+		return
+	}
+	bucket, ok := requestBucket(r)
+	if !ok {
+		http.Error(w, "bucket not allowed", 400)
+		return
+	}
+
+	obj, err := s3Client().GetObject(r.Context(), &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	countBackendError("s3", err)
+	if err != nil {
+		http.Error(w, "Failed to fetch report: "+err.Error(), 502)
+		return
+	}
+	defer obj.Body.Close()
+
+	ct := aws.ToString(obj.ContentType)
+	if ct == "" {
+		ct = mime.TypeByExtension(path.Ext(key))
+	}
+	if ct != "" {
+		w.Header().Set("Content-Type", ct)
+	}
+
+	var body io.Reader = obj.Body
+	if strings.EqualFold(aws.ToString(obj.ContentEncoding), "gzip") {
+		w.Header().Set("Vary", "Accept-Encoding")
+		if acceptsGzip(r) {
+			w.Header().Set("Content-Encoding", "gzip")
+		} else {
+			zr, err := gzip.NewReader(obj.Body)
+			if err != nil {
+				http.Error(w, "Failed to decompress report: "+err.Error(), 502)
+				return
+			}
+			defer zr.Close()
+			body = zr
+		}
+	}
+
+	if _, err := io.Copy(w, body); err != nil {
+		slog.Error("proxy report failed", "backend", "s3", "key", key, "error", err)
+	}
+}
+
+// objectHandler shows a text object from the bucket (a config JSON, a CSV
+// of results, a log) inline. Objects above limits.ObjectMaxBytes show their
+// first limits.ObjectMaxBytes; compressed or non-UTF-8 objects get a
+// download link through the proxy instead. A complete JSON document is
+// pretty-printed.
+func objectHandler(w http.ResponseWriter, r *http.Request) {
+	if s3Client() == nil {
+		content := `<div class="card"><h2>Object</h2><p style="color:#6b7280">S3 not configured.</p></div>`
+		page := layout("Object", content, backendStatus())
+		fmt.Fprint(w, page)
+		return
+	}
+
+	key := r.URL.Query().Get("key")
+	if key == "" {
+		http.Error(w, "missing key param", 400)
+		return
+	}
+	bucket, ok := requestBucket(r)
+	if !ok {
+		http.Error(w, "bucket not allowed", 400)
+		return
+	}
+
+	ctx := r.Context()
+	head, err := s3Client().HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	countBackendError("s3", err)
+	if err != nil {
+		content := `<div class="card"><h2>Object: ` + template.HTMLEscapeString(key) + `</h2><p style="color:#6b7280">` + template.HTMLEscapeString(err.Error()) + `</p></div>`
+		page := layout("Object", content, backendStatus())
+		fmt.Fprint(w, page)
+		return
+	}
+
+	dq := url.Values{"key": {key}}
+	if bucket != s3Bucket {
+		dq.Set("bucket", bucket)
+	}
+	download := "/load-test/proxy?" + dq.Encode()
+	size := aws.ToInt64(head.ContentLength)
+	if strings.EqualFold(aws.ToString(head.ContentEncoding), "gzip") {
+		content := fmt.Sprintf(`<div class="card"><h2>Object: %s</h2><p style="color:#6b7280">Object is compressed. <a href="%s">Download</a></p></div>`,
+			template.HTMLEscapeString(key), template.HTMLEscapeString(download))
+		page := layout("Object", content, backendStatus())
+		fmt.Fprint(w, page)
+		return
+	}
+
+	in := &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}
+	if size > limits.ObjectMaxBytes {
+		in.Range = aws.String(fmt.Sprintf("bytes=0-%d", limits.ObjectMaxBytes-1))
+	}
+	obj, err := s3Client().GetObject(ctx, in)
+	countBackendError("s3", err)
+	if err != nil {
+		content := `<div class="card"><h2>Object: ` + template.HTMLEscapeString(key) + `</h2><p style="color:#6b7280">` + template.HTMLEscapeString(err.Error()) + `</p></div>`
+		page := layout("Object", content, backendStatus())
+		fmt.Fprint(w, page)
+		return
+	}
+	defer obj.Body.Close()
+
+	// the object may have grown since HeadObject; never read past the limit
+	data, err := io.ReadAll(io.LimitReader(obj.Body, limits.ObjectMaxBytes))
+	truncated := size > int64(len(data))
+	if truncated {
+		// the cut may split a multi-byte character
+		for i := 0; i < utf8.UTFMax-1 && len(data) > 0 && !utf8.Valid(data); i++ {
+			data = data[:len(data)-1]
+		}
+	}
+	if err != nil || !utf8.Valid(data) {
+		content := fmt.Sprintf(`<div class="card"><h2>Object: %s</h2><p style="color:#6b7280">Not a readable text object. <a href="%s">Download</a></p></div>`,
+			template.HTMLEscapeString(key), template.HTMLEscapeString(download))
+		page := layout("Object", content, backendStatus())
+		fmt.Fprint(w, page)
+		return
+	}
+
+	text := string(data)
+	if !truncated && isJSONDocument(text) {
+		var buf bytes.Buffer
+		if json.Indent(&buf, data, "", "  ") == nil {
+			text = buf.String()
+		}
+	}
+	note := ""
+	if truncated {
+		note = fmt.Sprintf(`<p style="color:#b45309">Showing the first %s of %s.</p>`, humanBytes(int64(len(data))), humanBytes(size))
+	}
+
+	content := fmt.Sprintf(`
+<div class="card">
+  <h2>📄 Object: %s (%d bytes)</h2>
+  %s
+  <div style="margin-bottom:10px">
+    <button class="copy-btn" onclick="copyTextById('objectData')">Copy</button>
+    <a href="%s" style="margin-left:8px">Download</a>
+  </div>
+  <pre id="objectData" class="json">%s</pre>
+</div>
+`, template.HTMLEscapeString(key), size, note, template.HTMLEscapeString(download), template.HTMLEscapeString(text))
+
+	page := layout("Object: "+key, content, backendStatus())
+	fmt.Fprint(w, page)
+}
+
+func acceptsGzip(r *http.Request) bool {
+	for _, enc := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		enc = strings.TrimSpace(strings.SplitN(enc, ";", 2)[0])
+		if enc == "gzip" || enc == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+/////////////////////////////////////////////////////////////
+// Mongo viewer
+/////////////////////////////////////////////////////////////
+
+// collectionsPage renders the collection list of a database.
+const collectionsPage = `
+<div class="card">
+  <h2>📦 MongoDB Collections ({{.DB}}){{if .Default}} <span class="chip" title="No ?db= given: {{.DefaultFrom}}">default</span>{{end}}</h2>
+  <form method="get" style="margin-bottom:8px">
+    {{if .System}}<input type="hidden" name="system" value="true"/>{{end}}
+    <label style="color:#6b7280;font-size:13px">Database
+      <select name="db" onchange="this.form.submit()">
+        {{range .DBs}}<option value="{{.}}"{{if eq . $.DB}} selected{{end}}>{{.}}</option>{{end}}
+      </select>
+    </label>
+  </form>
+  <div style="margin-bottom:10px">
+    <a href="/db-data?list=1{{if .System}}&system=true{{end}}">← All databases</a>
+    · {{if .System}}<a href="/db-data?db={{.DB}}">Hide system collections</a>{{else}}<a href="/db-data?db={{.DB}}&system=true">Show system collections</a>{{end}}
+  </div>
+  <div class="row">
+    <form method="get" style="flex:1;display:flex">
+      <input type="hidden" name="db" value="{{.DB}}"/>
+      {{if .System}}<input type="hidden" name="system" value="true"/>{{end}}
+      <input id="mongoSearch" name="q" value="{{.Q}}" class="search" placeholder="Filter collections... (Enter to search server-side)" onkeyup="filterList('mongoSearch','mItem')"/>
+    </form>
+    <button class="copy-btn" style="white-space:nowrap" onclick="copyViewLink()">🔗 Copy link</button>
+  </div>
+
+  <div class="list">
+    {{range .Cols}}
+      <div class="list-item mItem">
+        <div><a href="/db-data/collection?db={{$.DB}}&name={{.Name}}">{{highlight .Name $.Q}}</a>{{if .System}} <span class="chip">system</span>{{end}}{{if eq .Kind "view"}} <span class="chip" title="{{.Detail}}">view</span>{{else if eq .Kind "timeseries"}} <span class="chip" title="{{.Detail}}">time-series</span>{{end}}{{if .Detail}} <span style="color:#6b7280;font-size:12px">{{.Detail}}</span>{{end}}</div>
+        <div class="badge">{{if lt .RowCount 0}}?{{else}}{{.RowCount}}{{end}}</div>
+      </div>
+    {{end}}
+  </div>
+</div>
+`
+
+func dbDataHandler(w http.ResponseWriter, r *http.Request) {
+	if mongoClient() == nil {
+		content := `<div class="card"><h2>MongoDB Collections</h2><p style="color:#6b7280">MongoDB not configured or unreachable. Set DATABASE_URL or check network access.</p></div>`
+		page := layout("MongoDB Collections", content, backendStatus())
+		fmt.Fprint(w, page)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), backendTimeout)
+	defer cancel()
+	dbs, err := mongoClient().ListDatabaseNames(ctx, bson.M{})
+	countBackendError("mongo", err)
+	if isTimeout(err) {
+		renderTimeout(w, "MongoDB Collections")
+		return
+	}
+	if err != nil || len(dbs) == 0 {
+		content := `<div class="card"><h2>MongoDB Collections</h2><p style="color:#6b7280">No databases found or failed to list databases.</p></div>`
+		page := layout("MongoDB Collections", content, backendStatus())
+		fmt.Fprint(w, page)
+		return
+	}
+
+	// without ?db= use the default database (see defaultDB); ?list=1 or
+	// having no application database shows the database list instead
+	dbName := r.URL.Query().Get("db")
+	isDefault := false
+	if dbName == "" && r.URL.Query().Get("list") == "" {
+		dbName, isDefault = defaultDB(dbs), true
+	}
+	if dbName == "" {
+		renderDBList(w, r, dbs)
+		return
+	}
+
+	specs, err := mongoClient().Database(dbName).ListCollectionSpecifications(ctx, bson.M{})
+	countBackendError("mongo", err)
+	if isTimeout(err) {
+		renderTimeout(w, "MongoDB Collections")
+		return
+	}
+	if err != nil {
+		content := `<div class="card"><h2>MongoDB Collections</h2><p style="color:#6b7280">Failed to list collections: ` + template.HTMLEscapeString(err.Error()) + `</p></div>`
+		page := layout("MongoDB Collections", content, backendStatus())
+		fmt.Fprint(w, page)
+		return
+	}
+
+	// build ColView slice with counts (estimated)
+	q := r.URL.Query().Get("q")
+	system := r.URL.Query().Get("system") == "true"
+	var colViews []ColView
+	for _, spec := range specs {
+		c := spec.Name
+		if q != "" && !matchesQuery(c, q) {
+			continue
+		}
+		// system.* collections (views, profile, ...) are hidden by default
+		sysColl := strings.HasPrefix(c, "system.")
+		if sysColl && !system {
+			continue
+		}
+		kind, detail := collectionKind(spec)
+		colViews = append(colViews, ColView{
+			Name:     c,
+			RowCount: collectionCount(ctx, mongoClient().Database(dbName).Collection(c), kind),
+			System:   sysColl || isSystemDB(dbName),
+			Kind:     kind,
+			Detail:   detail,
+		})
+	}
+
+	// the selector offers the application databases, plus system ones
+	// when shown, and always the current one
+	var choices []string
+	for _, d := range dbs {
+		if system || !isSystemDB(d) || d == dbName {
+			choices = append(choices, d)
+		}
+	}
+	if !slices.Contains(choices, dbName) {
+		choices = append(choices, dbName)
+	}
+	sort.Strings(choices)
+	defaultFrom := "the first application database"
+	if mongoDefaultDB != "" {
+		defaultFrom = "the database in DATABASE_URL"
+	}
+
+	renderPage(w, "db", map[string]interface{}{
+		"DB":          dbName,
+		"DBs":         choices,
+		"Default":     isDefault,
+		"DefaultFrom": defaultFrom,
+		"Cols":        colViews,
+		"Q":           q,
+		"System":      system,
+	})
+}
+
+type DBView struct {
+	Name        string
+	Collections int
+	System      bool
+}
+
+const dbPageSize = 50
+
+func isSystemDB(name string) bool {
+	return name == "admin" || name == "local" || name == "config"
+}
+
+// databasesPage renders the database list.
+const databasesPage = `
+<div class="card">
+  <h2>🗄 MongoDB Databases ({{.Total}})</h2>
+  <div class="row">
+    <form method="get" style="flex:1;display:flex">
+      <input type="hidden" name="list" value="1"/>
+      {{if .System}}<input type="hidden" name="system" value="true"/>{{end}}
+      <input id="dbSearch" name="q" value="{{.Q}}" class="search" placeholder="Filter databases... (Enter to search server-side)" onkeyup="filterList('dbSearch','dItem')"/>
+    </form>
+    <button class="copy-btn" style="white-space:nowrap" onclick="copyViewLink()">🔗 Copy link</button>
+  </div>
+  <div style="margin:6px 0">
+    {{if .System}}<a href="/db-data?list=1">Hide system databases</a>{{else}}<a href="/db-data?list=1&system=true">Show system databases</a>{{end}}
+  </div>
+
+  <div class="list">
+    {{range .DBs}}
+      <div class="list-item dItem">
+        <div><a href="/db-data?db={{.Name}}{{if $.System}}&system=true{{end}}">{{highlight .Name $.Q}}</a>{{if .System}} <span class="chip">system</span>{{end}}</div>
+        <div class="badge">{{.Collections}} collections</div>
+      </div>
+    {{else}}
+      <p style="color:#6b7280">{{if .Q}}No databases match.{{else}}No application databases found.{{end}}</p>
+    {{end}}
+  </div>
+
+  {{if gt .Pages 1}}
+  <div class="row" style="justify-content:center;margin-top:12px">
+    {{if gt .Page 1}}<a href="/db-data?list=1&page={{.Prev}}{{if .System}}&system=true{{end}}{{if .Q}}&q={{.Q}}{{end}}">← Prev</a>{{end}}
+    <span style="color:#6b7280">Page {{.Page}} of {{.Pages}}</span>
+    {{if lt .Page .Pages}}<a href="/db-data?list=1&page={{.Next}}{{if .System}}&system=true{{end}}{{if .Q}}&q={{.Q}}{{end}}">Next →</a>{{end}}
+  </div>
+  {{end}}
+</div>
+`
+
+// renderDBList renders a searchable (?q=), paginated list of the databases
+// with their collection counts. System databases are hidden unless
+// ?system=true. Counts are only fetched for the visible page.
+func renderDBList(w http.ResponseWriter, r *http.Request, dbs []string) {
+	ctx, cancel := context.WithTimeout(r.Context(), backendTimeout)
+	defer cancel()
+
+	system := r.URL.Query().Get("system") == "true"
+	q := r.URL.Query().Get("q")
+	var names []string
+	for _, d := range dbs {
+		if (system || !isSystemDB(d)) && (q == "" || matchesQuery(d, q)) {
+			names = append(names, d)
+		}
+	}
+	sort.Strings(names)
+
+	pages := (len(names) + dbPageSize - 1) / dbPageSize
+	if pages == 0 {
+		pages = 1
+	}
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	if page > pages {
+		page = pages
+	}
+	lo := (page - 1) * dbPageSize
+	hi := lo + dbPageSize
+	if hi > len(names) {
+		hi = len(names)
+	}
+
+	var views []DBView
+	for _, d := range names[lo:hi] {
+		cols, err := mongoClient().Database(d).ListCollectionNames(ctx, bson.M{})
+		countBackendError("mongo", err)
+		if err != nil {
+			slog.Error("list collections failed", "backend", "mongo", "db", d, "error", err)
+		}
+		views = append(views, DBView{Name: d, Collections: len(cols), System: isSystemDB(d)})
+	}
+
+	renderPage(w, "dbs", map[string]interface{}{
+		"DBs":    views,
+		"Total":  len(names),
+		"Page":   page,
+		"Pages":  pages,
+		"Prev":   page - 1,
+		"Next":   page + 1,
+		"System": system,
+		"Q":      q,
+	})
+}
+
+func dbCollectionHandler(w http.ResponseWriter, r *http.Request) {
+	if mongoClient() == nil {
+		content := `<div class="card"><h2>Collection</h2><p style="color:#6b7280">Mongo not configured.</p></div>`
+		page := layout("Collection", content, backendStatus())
+		fmt.Fprint(w, page)
+		return
+	}
+
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		http.Error(w, "missing collection name", 400)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), backendTimeout)
+	defer cancel()
+	dbName, err := requestDB(ctx, r)
+	if isTimeout(err) {
+		renderTimeout(w, "Collection: "+name)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	// optional ?filter= as (extended) JSON, e.g. {"status":"failed"}
+	filter, err := parseFilter(r.URL.Query().Get("filter"))
+	if err != nil {
+		content := `<div class="card"><h2>Collection: ` + template.HTMLEscapeString(name) + `</h2><p style="color:#b91c1c">Invalid filter: ` + template.HTMLEscapeString(err.Error()) + `</p>` +
+			filterForm(r, dbName, name) + `</div>`
+		page := layout("Collection", content, backendStatus())
+		fmt.Fprint(w, page)
+		return
+	}
+
+	coll := mongoClient().Database(dbName).Collection(name)
+	strategy := sampleStrategy(r)
+	skip, limit := docWindow(r)
+
+	// SAMPLE_CACHE_TTL enables a short-lived cache of samples; ?nocache=1
+	// (the refresh link) always goes to Mongo and refreshes the entry.
+	// Random samples are never cached, each load draws a new one.
+	cacheKey := strings.Join([]string{dbName, name, r.URL.Query().Get("filter"), strconv.FormatInt(skip, 10), strconv.FormatInt(limit, 10), strategy}, "\x00")
+	useCache := samples != nil && strategy != "random"
+	var smp sample
+	var cachedAt time.Time
+	cached := false
+	if useCache && !noCache(r) {
+		smp, cachedAt, cached = samples.get(cacheKey)
+	}
+
+	// an uncached sample is streamed from the cursor into the page as it
+	// is read rather than held in memory; the query runs first so that a
+	// failure still gets a proper error page
+	var cur *mongo.Cursor
+	var took time.Duration
+	if !cached {
+		start := time.Now()
+		cur, err = sampleDocs(ctx, coll, filter, strategy, skip, limit)
+		took = time.Since(start)
+		if isTimeout(err) {
+			renderTimeout(w, "Collection: "+name)
+			return
+		}
+		if err != nil {
+			content := `<div class="card"><h2>Collection: ` + template.HTMLEscapeString(name) + `</h2><p style="color:#6b7280">` + template.HTMLEscapeString(err.Error()) + `</p></div>`
+			page := layout("Collection", content, backendStatus())
+			fmt.Fprint(w, page)
+			return
+		}
+	}
+
+	// only count matches when filtering; bounded so a loose filter on a huge
+	// collection can't turn into a full scan
+	stats := strategy + " sample"
+	if cached {
+		stats += fmt.Sprintf(" · cached %s ago", time.Since(cachedAt).Round(time.Second))
+	}
+	if len(filter) > 0 {
+		stats += " · " + countMatches(ctx, coll, filter)
+	} else {
+		n, err := coll.EstimatedDocumentCount(ctx)
+		countBackendError("mongo", err)
+		if err == nil {
+			stats += fmt.Sprintf(" · ≈ %d documents", n)
+		}
+	}
+
+	refresh := ""
+	if samples != nil {
+		q := r.URL.Query()
+		q.Set("nocache", "1")
+		refresh = `<a href="/db-data/collection?` + template.HTMLEscapeString(q.Encode()) + `" style="margin-left:8px">↻ Refresh</a>`
+	}
+
+	// sampling strategy switcher, keeping the rest of the query
+	var picks []string
+	for _, st := range []string{"oldest", "latest", "random"} {
+		if st == strategy {
+			picks = append(picks, "<b>"+st+"</b>")
+			continue
+		}
+		q := r.URL.Query()
+		q.Set("sample", st)
+		q.Del("nocache")
+		picks = append(picks, `<a href="/db-data/collection?`+template.HTMLEscapeString(q.Encode())+`">`+st+`</a>`)
+	}
+
+	// opt-in facet panel: value counts of one field under the current filter
+	facetPanel := ""
+	if field := r.URL.Query().Get("facet"); field != "" {
+		facetPanel = renderFacetPanel(ctx, coll, dbName, name, filter, field)
+	}
+
+	content := fmt.Sprintf(`
+<div class="card">
+  <h2>📁 Collection: %s (%s)</h2>
+  <div style="margin-bottom:10px">
+    <a href="/db-data?db=%s">← %s</a>
+    <button class="copy-btn" onclick="copyTextById('jsonData')">Copy JSON</button>
+    <button class="copy-btn" onclick="copyViewLink()">🔗 Copy link</button>
+    <a href="/db-data/watch?%s" style="margin-left:8px">👁 Watch live</a>
+    %s
+    %s
+    %s
+  </div>
+  <div style="margin-bottom:10px;color:#6b7280;font-size:13px">Sample: %s</div>
+  %s
+  <form method="get" class="row">
+    <input type="hidden" name="db" value="%s"/>
+    <input type="hidden" name="name" value="%s"/>
+    <input type="hidden" name="filter" value="%s"/>
+    <input type="hidden" name="sample" value="%s"/>
+    <input name="facet" class="search" style="max-width:260px" placeholder="Facet by field, e.g. status" value="%s"/>
+    <button class="copy-btn" type="submit">Facet</button>
+  </form>
+  <div style="display:flex;gap:12px;align-items:flex-start">
+    <pre id="jsonData" class="json" style="flex:1;margin:0">%s</pre>
+    %s
+  </div>
+  %s
+</div>
+`, template.HTMLEscapeString(name), template.HTMLEscapeString(stats),
+		template.HTMLEscapeString(url.QueryEscape(dbName)), template.HTMLEscapeString(dbName),
+		template.HTMLEscapeString(url.Values{"db": {dbName}, "name": {name}}.Encode()), refresh, compactToggle(r)+extToggle(r), validateLink(dbName, name),
+		strings.Join(picks, " · "), filterForm(r, dbName, name),
+		template.HTMLEscapeString(dbName), template.HTMLEscapeString(name),
+		template.HTMLEscapeString(r.URL.Query().Get("filter")), strategy, template.HTMLEscapeString(r.URL.Query().Get("facet")),
+		docsMarker, facetPanel, footerMarker)
+
+	// send the page up to the <pre> right away, then the documents as they
+	// are read, then what depends on how many there were
+	page := layout("Collection: "+name, content, backendStatus())
+	head, rest, _ := strings.Cut(page, docsMarker)
+	mid, tail, _ := strings.Cut(rest, footerMarker)
+	io.WriteString(w, head)
+	http.NewResponseController(w).Flush()
+
+	ds := &docStream{w: w, indent: jsonIndent(r), canonical: extCanonical(r), dbName: dbName, name: name, filter: filter}
+	truncated := smp.truncated
+	var readErr error
+	start := time.Now()
+	if cached {
+		for _, doc := range smp.docs {
+			ds.write(redactDoc(doc))
+		}
+	} else {
+		var fresh sample
+		truncated, readErr = eachSampleDoc(ctx, cur, func(doc bson.M) {
+			if useCache {
+				fresh.docs = append(fresh.docs, doc)
+			}
+			ds.write(redactDoc(doc))
+		})
+		fresh.truncated = truncated
+		if readErr == nil && useCache {
+			samples.put(cacheKey, fresh)
+		}
+	}
+	ds.close()
+	took += time.Since(start)
+	io.WriteString(w, mid)
+	io.WriteString(w, withBasePath(sampleFooter(r, ds.n, took, cached, truncated, readErr, strategy, skip, limit)))
+	io.WriteString(w, tail)
+}
+
+// markers in the collection page where the streamed documents and the
+// footer describing them go
+const (
+	docsMarker   = "<!--docs-->"
+	footerMarker = "<!--sample-footer-->"
+)
+
+// sampleFooter describes a streamed sample once it is written: rows and
+// read time, a read error or the MAX_RESPONSE_BYTES truncation, and the
+// Prev/Next pager, which steps skip by the page size (a random sample has
+// no pages).
+func sampleFooter(r *http.Request, n int, took time.Duration, cached, truncated bool, readErr error, strategy string, skip, limit int64) string {
+	rows := fmt.Sprintf("%d rows", n)
+	if !cached {
+		rows += " · " + took.Round(time.Millisecond).String()
+	}
+	more := int64(n) == limit || truncated
+	if strategy != "random" && (skip > 0 || more) {
+		rows += fmt.Sprintf(" · documents %d–%d", skip+1, skip+int64(n))
+	}
+	out := `<div style="margin-top:8px;color:#6b7280;font-size:13px">` + rows + `</div>`
+
+	switch {
+	case isTimeout(readErr):
+		out += `<p style="color:#b91c1c">Timed out reading the sample — showing the documents read so far.</p>`
+	case readErr != nil:
+		out += `<p style="color:#b91c1c">Failed to read docs: ` + template.HTMLEscapeString(readErr.Error()) + `</p>`
+	case truncated:
+		out += fmt.Sprintf(`<p style="color:#b45309">… truncated: the sample reached MAX_RESPONSE_BYTES (%d bytes) after %d documents — page on with Next or narrow the filter</p>`, limits.MaxResponseBytes, n)
+	}
+
+	if strategy == "random" {
+		return out
+	}
+	step := func(to int64, label string) string {
+		q := r.URL.Query()
+		q.Set("skip", strconv.FormatInt(to, 10))
+		q.Del("nocache")
+		return `<a href="/db-data/collection?` + template.HTMLEscapeString(q.Encode()) + `">` + label + `</a>`
+	}
+	var links []string
+	if skip > 0 {
+		links = append(links, step(max(skip-limit, 0), "← Previous"))
+	}
+	if more {
+		links = append(links, step(skip+int64(n), "Next →"))
+	}
+	if len(links) > 0 {
+		out += `<div class="row" style="justify-content:center;margin-top:12px">` + strings.Join(links, " · ") + `</div>`
+	}
+	return out
+}
+
+// sampleStrategy returns the ?sample= strategy: "random", "latest" or the
+// default "oldest" (natural order, the first documents inserted).
+func sampleStrategy(r *http.Request) string {
+	return sampleStrategyOf(r.URL.Query().Get("sample"))
+}
+
+// sampleStrategyOf is sampleStrategy for a value read elsewhere, e.g. the
+// validate form's POST body.
+func sampleStrategyOf(s string) string {
+	switch s {
+	case "random", "latest":
+		return s
+	}
+	return "oldest"
+}
+
+// serverJSOperators run JavaScript on the server and are refused in filters.
+var serverJSOperators = map[string]bool{"$where": true, "$function": true, "$accumulator": true}
+
+// parseFilter parses a ?filter= (extended JSON, "" = match all) into a
+// document. The filter is only ever used as a parsed document, and
+// operators that execute server-side JavaScript are rejected.
+func parseFilter(raw string) (bson.M, error) {
+	filter := bson.M{}
+	if raw == "" {
+		return filter, nil
+	}
+	if err := bson.UnmarshalExtJSON([]byte(raw), false, &filter); err != nil {
+		return nil, err
+	}
+	if op := findOperator(filter, serverJSOperators); op != "" {
+		return nil, fmt.Errorf("operator %s is not allowed", op)
+	}
+	return filter, nil
+}
+
+// findOperator returns the first key of v, at any depth, that is in ops.
+func findOperator(v interface{}, ops map[string]bool) string {
+	switch t := v.(type) {
+	case bson.M:
+		for k, e := range t {
+			if ops[k] {
+				return k
+			}
+			if op := findOperator(e, ops); op != "" {
+				return op
+			}
+		}
+	case bson.D:
+		for _, e := range t {
+			if ops[e.Key] {
+				return e.Key
+			}
+			if op := findOperator(e.Value, ops); op != "" {
+				return op
+			}
+		}
+	case bson.A:
+		for _, e := range t {
+			if op := findOperator(e, ops); op != "" {
+				return op
+			}
+		}
+	}
+	return ""
+}
+
+// filterForm renders the filter input of a collection page, keeping the
+// sampling strategy and page size; a new filter starts at the first page.
+func filterForm(r *http.Request, dbName, name string) string {
+	limit := ""
+	if v := r.URL.Query().Get("limit"); v != "" {
+		limit = `<input type="hidden" name="limit" value="` + template.HTMLEscapeString(v) + `"/>`
+	}
+	clear := ""
+	if r.URL.Query().Get("filter") != "" {
+		clear = `<a href="/db-data/collection?` + template.HTMLEscapeString(url.Values{"db": {dbName}, "name": {name}}.Encode()) + `" style="white-space:nowrap">clear</a>`
+	}
+	return fmt.Sprintf(`<form method="get" class="row" style="margin-bottom:10px">
+    <input type="hidden" name="db" value="%s"/>
+    <input type="hidden" name="name" value="%s"/>
+    <input type="hidden" name="sample" value="%s"/>
+    %s
+    <input name="filter" class="search" style="font-family:monospace" placeholder='Filter as JSON, e.g. {"status":"failed"}' value="%s"/>
+    <button class="copy-btn" type="submit">Filter</button>
+    %s
+  </form>`, template.HTMLEscapeString(dbName), template.HTMLEscapeString(name), sampleStrategy(r), limit,
+		template.HTMLEscapeString(r.URL.Query().Get("filter")), clear)
+}
+
+// docWindow returns the ?skip= and ?limit= of a collection page. limit
+// defaults to limits.MongoPageSize and is capped at limits.MongoMaxPage.
+func docWindow(r *http.Request) (skip, limit int64) {
+	skip, _ = strconv.ParseInt(r.URL.Query().Get("skip"), 10, 64)
+	if skip < 0 {
+		skip = 0
+	}
+	limit, _ = strconv.ParseInt(r.URL.Query().Get("limit"), 10, 64)
+	if limit < 1 {
+		limit = limits.MongoPageSize
+	}
+	if limit > limits.MongoMaxPage {
+		limit = limits.MongoMaxPage
+	}
+	return skip, limit
+}
+
+// sampleDocs opens a cursor over up to limit documents matching filter
+// after skipping skip, picked by strategy: a $sample aggregation for random
+// (skip is ignored), _id descending for latest, natural order for oldest.
+func sampleDocs(ctx context.Context, coll *mongo.Collection, filter bson.M, strategy string, skip, limit int64) (*mongo.Cursor, error) {
+	var cur *mongo.Cursor
+	var err error
+	switch strategy {
+	case "random":
+		cur, err = coll.Aggregate(ctx, mongo.Pipeline{
+			{{Key: "$match", Value: filter}},
+			{{Key: "$sample", Value: bson.M{"size": limit}}},
+		})
+	case "latest":
+		cur, err = coll.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "_id", Value: -1}}).SetSkip(skip).SetLimit(limit))
+	default:
+		cur, err = coll.Find(ctx, filter, options.Find().SetSkip(skip).SetLimit(limit))
+	}
+	return cur, backendErr("mongo", err)
+}
+
+// readSample decodes documents from cur until it is exhausted or their
+// total BSON size would exceed limits.MaxResponseBytes, so a page of huge
+// documents can't exhaust memory. The cursor is closed.
+func readSample(ctx context.Context, cur *mongo.Cursor) (sample, error) {
+	var s sample
+	var err error
+	s.truncated, err = eachSampleDoc(ctx, cur, func(doc bson.M) {
+		s.docs = append(s.docs, doc)
+	})
+	return s, err
+}
+
+// eachSampleDoc is readSample for callers that handle one document at a
+// time: fn gets each document as it is decoded and truncated reports
+// whether the budget stopped the read. The cursor is closed.
+func eachSampleDoc(ctx context.Context, cur *mongo.Cursor, fn func(bson.M)) (truncated bool, err error) {
+	defer cur.Close(ctx)
+	var size int64
+	for cur.Next(ctx) {
+		size += int64(len(cur.Current))
+		if size > limits.MaxResponseBytes {
+			return true, nil
+		}
+		var doc bson.M
+		if err := cur.Decode(&doc); err != nil {
+			return false, err
+		}
+		fn(doc)
+	}
+	return false, backendErr("mongo", cur.Err())
+}
+
+// requestDB returns the database selected by ?db=, falling back to
+// defaultDB so every Mongo page agrees on the database without one.
+func requestDB(ctx context.Context, r *http.Request) (string, error) {
+	if db := r.URL.Query().Get("db"); db != "" {
+		return db, nil
+	}
+	if mongoDefaultDB != "" {
+		return mongoDefaultDB, nil
+	}
+	dbs, err := mongoClient().ListDatabaseNames(ctx, bson.M{})
+	if err != nil {
+		return "", backendErr("mongo", err)
+	}
+	db := defaultDB(dbs)
+	if db == "" {
+		return "", fmt.Errorf("no application databases")
+	}
+	return db, nil
+}
+
+// defaultDB is the database used without ?db=: the one named in
+// DATABASE_URL, else the first non-system one of dbs (by name), else "".
+func defaultDB(dbs []string) string {
+	if mongoDefaultDB != "" {
+		return mongoDefaultDB
+	}
+	first := ""
+	for _, d := range dbs {
+		if !isSystemDB(d) && (first == "" || d < first) {
+			first = d
+		}
+	}
+	return first
+}
+
+// watchPage renders the live change-stream view.
+const watchPage = `
+<div class="card">
+  <h2>👁 Watching: {{.Name}}</h2>
+  <div class="row">
+    <span id="watchStatus" style="color:#6b7280">Connecting…</span>
+  </div>
+  <div id="events" class="list"></div>
+</div>
+<script>
+  (function() {
+    var status = document.getElementById("watchStatus");
+    var list = document.getElementById("events");
+    var es = new EventSource({{.StreamURL}});
+    es.onopen = function() { status.textContent = "Live — waiting for changes"; };
+    es.onmessage = function(e) {
+      var ev = JSON.parse(e.data);
+      var item = document.createElement("div");
+      item.className = "list-item";
+      item.style.display = "block";
+      var head = document.createElement("div");
+      head.innerHTML = '<span class="badge"></span>';
+      head.firstChild.textContent = ev.operationType;
+      var pre = document.createElement("pre");
+      pre.className = "json";
+      delete ev.operationType;
+      pre.textContent = JSON.stringify(ev, null, 2);
+      item.appendChild(head);
+      item.appendChild(pre);
+      list.insertBefore(item, list.firstChild);
+      while (list.children.length > 200) { list.removeChild(list.lastChild); }
+    };
+    es.addEventListener("unavailable", function(e) {
+      status.textContent = "Change stream unavailable: " + e.data;
+      es.close();
+    });
+    es.onerror = function() { status.textContent = "Disconnected — retrying…"; };
+  })();
+</script>
+`
+
+// dbWatchHandler renders a live view of a collection's change stream. The
+// events themselves are pushed by dbWatchEventsHandler over SSE.
+func dbWatchHandler(w http.ResponseWriter, r *http.Request) {
+	if mongoClient() == nil {
+		content := `<div class="card"><h2>Watch</h2><p style="color:#6b7280">Mongo not configured.</p></div>`
+		page := layout("Watch", content, backendStatus())
+		fmt.Fprint(w, page)
+		return
+	}
+
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		http.Error(w, "missing collection name", 400)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), backendTimeout)
+	defer cancel()
+	dbName, err := requestDB(ctx, r)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	renderPage(w, "watch", map[string]interface{}{
+		"Name":      name,
+		"StreamURL": basePath + "/db-data/watch/events?" + url.Values{"db": {dbName}, "name": {name}}.Encode(),
+	})
+}
+
+// dbWatchEventsHandler streams insert/update/delete events of a collection as
+// server-sent events. Change streams need a replica set; on a standalone
+// server an "unavailable" event is sent so the page can stop retrying.
+func dbWatchEventsHandler(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", 500)
+		return
+	}
+	if mongoClient() == nil {
+		http.Error(w, "mongo not configured", 503)
+		return
+	}
+	name := r.URL.Query().Get("name")
 	if name == "" {
 		http.Error(w, "missing collection name", 400)
 		return
 	}
 
-	ctx := context.Background()
-	dbs, _ := mongoClient.ListDatabaseNames(ctx, bson.M{})
-	if len(dbs) == 0 {
-		http.Error(w, "no dbs", 500)
+	// the stream itself runs until the client goes away; only resolving
+	// the database is bounded
+	ctx := r.Context()
+	dctx, cancel := context.WithTimeout(ctx, backendTimeout)
+	dbName, err := requestDB(dctx, r)
+	cancel()
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	stream, err := mongoClient().Database(dbName).Collection(name).Watch(ctx, mongo.Pipeline{},
+		options.ChangeStream().
+			SetFullDocument(options.UpdateLookup).
+			SetMaxAwaitTime(15*time.Second))
+	countBackendError("mongo", err)
+	if err != nil {
+		fmt.Fprintf(w, "event: unavailable\ndata: %s\n\n", strings.ReplaceAll(err.Error(), "\n", " "))
+		flusher.Flush()
+		return
+	}
+	defer stream.Close(context.Background())
+	flusher.Flush()
+
+	for {
+		if stream.TryNext(ctx) {
+			var ev struct {
+				OperationType     string `bson:"operationType" json:"operationType"`
+				DocumentKey       bson.M `bson:"documentKey" json:"documentKey,omitempty"`
+				FullDocument      bson.M `bson:"fullDocument" json:"fullDocument,omitempty"`
+				UpdateDescription bson.M `bson:"updateDescription" json:"updateDescription,omitempty"`
+			}
+			if err := stream.Decode(&ev); err != nil {
+				slog.Error("watch decode failed", "backend", "mongo", "error", err)
+				continue
+			}
+			if ev.FullDocument != nil {
+				ev.FullDocument = redactValue(ev.FullDocument).(bson.M)
+			}
+			if ev.UpdateDescription != nil {
+				ev.UpdateDescription = redactValue(ev.UpdateDescription).(bson.M)
+			}
+			b, _ := json.Marshal(ev)
+			fmt.Fprintf(w, "data: %s\n\n", b)
+			flusher.Flush()
+			continue
+		}
+		if err := stream.Err(); err != nil {
+			if ctx.Err() == nil {
+				countBackendError("mongo", err)
+				slog.Error("watch failed", "backend", "mongo", "db", dbName, "collection", name, "error", err)
+			}
+			return
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		// nothing within MaxAwaitTime: keep intermediaries from timing out
+		fmt.Fprint(w, ": keepalive\n\n")
+		flusher.Flush()
+	}
+}
+
+const facetLimit = 25
+
+type facetValue struct {
+	Value interface{} `bson:"_id"`
+	Count int64       `bson:"count"`
+}
+
+// renderFacetPanel groups the filtered collection by field and renders the
+// most common values, each linking to the collection narrowed to that value.
+func renderFacetPanel(ctx context.Context, coll *mongo.Collection, dbName, name string, filter bson.M, field string) string {
+	panel := func(body string) string {
+		return `<div style="width:280px;flex-shrink:0"><h3 style="margin:0 0 8px 0">Facet: ` + template.HTMLEscapeString(field) + `</h3>` + body + `</div>`
+	}
+	if strings.HasPrefix(field, "$") {
+		return panel(`<p style="color:#6b7280">Invalid field name.</p>`)
+	}
+	if redactedPath(field) {
+		return panel(`<p style="color:#6b7280">This field is redacted.</p>`)
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$group", Value: bson.D{{Key: "_id", Value: "$" + field}, {Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}}}},
+		{{Key: "$limit", Value: facetLimit}},
+	}
+	cur, err := coll.Aggregate(ctx, pipeline, options.Aggregate().SetMaxTime(5*time.Second))
+	countBackendError("mongo", err)
+	if err != nil {
+		return panel(`<p style="color:#6b7280">` + template.HTMLEscapeString(err.Error()) + `</p>`)
+	}
+	var values []facetValue
+	if err := backendErr("mongo", cur.All(ctx, &values)); err != nil {
+		return panel(`<p style="color:#6b7280">` + template.HTMLEscapeString(err.Error()) + `</p>`)
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		narrowed := bson.M{}
+		for k, fv := range filter {
+			narrowed[k] = fv
+		}
+		narrowed[field] = v.Value
+		fj, err := bson.MarshalExtJSON(narrowed, false, false)
+		if err != nil {
+			continue
+		}
+		label := "(missing)"
+		if v.Value != nil {
+			label = fmt.Sprint(v.Value)
+		}
+		href := "/db-data/collection?" + url.Values{"db": {dbName}, "name": {name}, "filter": {string(fj)}, "facet": {field}}.Encode()
+		fmt.Fprintf(&b, `<div class="list-item"><a href="%s">%s</a><span class="badge">%d</span></div>`,
+			template.HTMLEscapeString(href), template.HTMLEscapeString(label), v.Count)
+	}
+	if b.Len() == 0 {
+		return panel(`<p style="color:#6b7280">No values.</p>`)
+	}
+	return panel(b.String())
+}
+
+// countMatches returns a display string with the number of documents
+// matching filter, capped at maxCount and bounded by a short timeout.
+func countMatches(ctx context.Context, coll *mongo.Collection, filter bson.M) string {
+	const maxCount = 100000
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	n, err := coll.CountDocuments(ctx, filter, options.Count().SetLimit(maxCount))
+	countBackendError("mongo", err)
+	if isTimeout(err) {
+		return "count timed out"
+	}
+	if err != nil {
+		slog.Error("count failed", "backend", "mongo", "error", err)
+		return "count unavailable"
+	}
+	if n >= maxCount {
+		return fmt.Sprintf("%d+ matching", maxCount)
+	}
+	return fmt.Sprintf("%d matching", n)
+}
+
+/////////////////////////////////////////////////////////////
+// Redis viewer
+/////////////////////////////////////////////////////////////
+
+// redisKeysPage renders the Redis key list.
+const redisKeysPage = `
+<div class="card">
+  <h2>⚡ Redis Keys{{if ne .Match "*"}} <span style="font-size:14px;color:#6b7280">MATCH <code>{{.Match}}</code></span>{{end}}</h2>
+  {{if .Notice}}<p style="color:#b45309">{{.Notice}}</p>{{end}}
+  {{if ge .DBSize 0}}
+  <div style="color:#6b7280;font-size:13px;margin-bottom:8px">
+    <b>{{.DBSize}}</b> keys in this database (DBSIZE){{if ge (len .Keys) .MaxKeys}} — the list below stops at {{.MaxKeys}}{{end}}
+    {{range .Keyspace}} · {{.DB}}: {{.Keys}} keys, {{.Expires}} with expiry{{end}}
+  </div>
+  {{end}}
+  <div class="row">
+    <form method="get" style="flex:1;display:flex">
+      {{if .Detail}}<input type="hidden" name="detail" value="true"/>{{end}}
+      {{if .SortTTL}}<input type="hidden" name="sort" value="ttl"/>{{end}}
+      {{if .CustomMax}}<input type="hidden" name="max" value="{{.MaxKeys}}"/>{{end}}
+      <input id="redisSearch" name="q" value="{{.Q}}" class="search" placeholder="Search keys... (Enter to search server-side)" onkeyup="filterList('redisSearch','rItem')"/>
+      <input name="match" value="{{.Match}}" class="search" style="max-width:180px;margin-left:6px" title="SCAN MATCH pattern" placeholder="MATCH pattern"/>
+    </form>
+    <button class="copy-btn" style="white-space:nowrap" onclick="copyViewLink()">🔗 Copy link</button>
+    <a href="/redis-data/search?match={{.Match}}" style="white-space:nowrap" title="Find keys by the contents of their values (full scan)">🔍 Search values</a>
+    <a href="/redis-data/export?match={{.Match}}" style="white-space:nowrap" title="Download matching keys as JSON">⬇ Export</a>
+    {{if .Write}}<a href="/redis-data/create" style="white-space:nowrap">＋ New key</a>{{end}}
+    {{if .Flush}}<a href="/redis-data/flush" style="white-space:nowrap;color:#b91c1c">🛑 Flush DB</a>{{end}}
+  </div>
+  <div style="margin:6px 0">
+    {{if .Detail}}<a href="/redis-data?q={{.Q}}&match={{.Match}}{{if .SortTTL}}&sort=ttl{{end}}{{if .CustomMax}}&max={{.MaxKeys}}{{end}}">Hide details</a>{{else}}<a href="/redis-data?q={{.Q}}&match={{.Match}}&detail=true{{if .SortTTL}}&sort=ttl{{end}}{{if .CustomMax}}&max={{.MaxKeys}}{{end}}">Show types &amp; sizes</a>{{end}}
+    ·
+    {{if .SortTTL}}<a href="/redis-data?q={{.Q}}&match={{.Match}}{{if .Detail}}&detail=true{{end}}{{if .CustomMax}}&max={{.MaxKeys}}{{end}}">Sort by name</a>{{else}}<a href="/redis-data?q={{.Q}}&match={{.Match}}{{if .Detail}}&detail=true{{end}}&sort=ttl{{if .CustomMax}}&max={{.MaxKeys}}{{end}}">Sort by TTL</a>{{end}}
+  </div>
+
+  <div class="list">
+    {{range .Keys}}
+      <div class="list-item rItem">
+        <div>{{if .Type}}<span title="{{.Type}}">{{typeIcon .Type}}</span> {{end}}<a href="/redis-data/key?k={{encodeKey .Name}}">{{highlight .Name $.Q}}</a></div>
+        <div style="white-space:nowrap">
+          {{if $.SortTTL}}<span class="badge">{{if lt .TTL 0}}no expiry{{else}}expires in {{.TTL}}{{end}}</span>{{end}}
+          {{if .Type}}<span class="badge">{{.Type}}{{if ne .Type "string"}} · {{.Size}}{{end}}</span>{{end}}
+        </div>
+      </div>
+    {{end}}
+  </div>
+</div>
+`
+
+func redisDataHandler(w http.ResponseWriter, r *http.Request) {
+	if redisClient() == nil {
+		content := `<div class="card"><h2>Redis Keys</h2><p style="color:#6b7280">Redis not configured. Set REDIS_URL.</p></div>`
+		page := layout("Redis Keys", content, backendStatus())
+		fmt.Fprint(w, page)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), backendTimeout)
+	defer cancel()
+	q := r.URL.Query().Get("q")
+	match := r.URL.Query().Get("match")
+	if match == "" {
+		match = redisDefaultMatch
+	}
+	if len(match) > maxMatchLen {
+		http.Error(w, fmt.Sprintf("match pattern longer than %d bytes", maxMatchLen), 400)
 		return
 	}
-	dbName := dbs[0]
+	// ?max= raises or lowers the key cap, up to maxRedisKeys
+	max := limits.RedisMaxKeys
+	if v, err := strconv.Atoi(r.URL.Query().Get("max")); err == nil && v > 0 {
+		max = min(v, maxRedisKeys)
+	}
+	keys, err := scanRedisKeys(ctx, match, q, max)
+	notice := ""
+	if isTimeout(err) {
+		notice = fmt.Sprintf("Scan timed out after %s — showing the %d keys found so far. Try a narrower search.", backendTimeout, len(keys))
+	}
+
+	views := make([]KeyView, len(keys))
+	for i, k := range keys {
+		views[i] = KeyView{Name: k}
+	}
+	// ?detail=true adds type and size, two pipelined round trips
+	detail := r.URL.Query().Get("detail") == "true"
+	if detail {
+		if err := keyDetails(ctx, views); err != nil {
+			slog.Error("redis key details failed", "backend", "redis", "error", err)
+			notice = "Could not load key details: " + err.Error()
+		}
+	}
+	// ?sort=ttl surfaces the keys about to expire first
+	sortTTL := r.URL.Query().Get("sort") == "ttl"
+	if sortTTL {
+		if err := keyTTLs(ctx, views); err != nil {
+			slog.Error("redis key ttls failed", "backend", "redis", "error", err)
+			notice = "Could not load key TTLs: " + err.Error()
+		}
+	}
+
+	// the real scale of the keyspace, since the list below is capped
+	dbSize, keyspace, err := keyspaceSummary(ctx)
+	if err != nil {
+		slog.Error("redis keyspace summary failed", "backend", "redis", "error", err)
+	}
+
+	renderPage(w, "redis", map[string]interface{}{
+		"Keys":      views,
+		"Q":         q,
+		"Match":     match,
+		"Notice":    notice,
+		"Write":     redisWrite,
+		"Flush":     redisWrite && redisFlush && !redisCluster,
+		"Detail":    detail,
+		"SortTTL":   sortTTL,
+		"DBSize":    dbSize,
+		"Keyspace":  keyspace,
+		"MaxKeys":   max,
+		"CustomMax": max != limits.RedisMaxKeys,
+	})
+}
+
+// KeyspaceDB is one database line of INFO keyspace.
+type KeyspaceDB struct {
+	DB      string
+	Keys    int64
+	Expires int64
+}
+
+// keyspaceSummary returns DBSIZE of the selected database and the key and
+// expiry counts of every database from INFO keyspace. dbSize is -1 when
+// DBSIZE fails. On a cluster DBSIZE is summed over the masters while INFO
+// comes from a single node.
+func keyspaceSummary(ctx context.Context) (dbSize int64, dbs []KeyspaceDB, err error) {
+	if dbSize, err = redisClient().DBSize(ctx).Result(); err != nil {
+		return -1, nil, backendErr("redis", err)
+	}
+	info, err := redisClient().Info(ctx, "keyspace").Result()
+	if err != nil {
+		return dbSize, nil, backendErr("redis", err)
+	}
+	// lines look like "db0:keys=12,expires=1,avg_ttl=0"
+	for _, line := range strings.Split(info, "\n") {
+		name, fields, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok || !strings.HasPrefix(name, "db") {
+			continue
+		}
+		ks := KeyspaceDB{DB: name}
+		for _, f := range strings.Split(fields, ",") {
+			k, v, _ := strings.Cut(f, "=")
+			n, _ := strconv.ParseInt(v, 10, 64)
+			switch k {
+			case "keys":
+				ks.Keys = n
+			case "expires":
+				ks.Expires = n
+			}
+		}
+		dbs = append(dbs, ks)
+	}
+	return dbSize, dbs, nil
+}
+
+// key list bounds: the longest accepted ?match= pattern and the highest ?max=
+const (
+	maxMatchLen  = 256
+	maxRedisKeys = 10000
+)
 
-	cur, err := mongoClient.Database(dbName).Collection(name).Find(ctx, bson.M{}, options.Find().SetLimit(200))
+// scanRedisKeys scans for keys matching the SCAN pattern match and
+// containing q (all matches when q is empty), up to max keys, across every
+// master of a cluster. On a scan error the keys found so far are returned
+// along with the error.
+func scanRedisKeys(ctx context.Context, match, q string, max int) ([]string, error) {
+	nodes, err := redisScanNodes(ctx)
 	if err != nil {
-		content := `<div class="card"><h2>Collection: ` + template.HTMLEscapeString(name) + `</h2><p style="color:#6b7280">` + template.HTMLEscapeString(err.Error()) + `</p></div>`
-		page := layout("Collection", content)
+		slog.Error("redis cluster nodes failed", "backend", "redis", "error", err)
+		return nil, err
+	}
+	var keys []string
+	for _, node := range nodes {
+		var cursor uint64
+		for {
+			k, c, err := node.Scan(ctx, cursor, match, 200).Result()
+			countBackendError("redis", err)
+			if err != nil {
+				slog.Error("redis scan failed", "backend", "redis", "match", match, "error", err)
+				return keys, err
+			}
+			// filter while scanning so the key cap applies to matches only
+			for _, key := range k {
+				if q == "" || matchesQuery(key, q) {
+					keys = append(keys, key)
+				}
+			}
+			if len(keys) >= max {
+				redisKeysScanned.Set(float64(max))
+				return keys[:max], nil
+			}
+			cursor = c
+			if cursor == 0 {
+				break
+			}
+		}
+	}
+	redisKeysScanned.Set(float64(len(keys)))
+	return keys, nil
+}
+
+// KeyView is a key on the key list. Type and Size are only set with
+// ?detail=true; Size is the element count of collection types. TTL is only
+// set with ?sort=ttl and is negative for keys without an expiry.
+type KeyView struct {
+	Name string
+	Type string
+	Size int64
+	TTL  time.Duration
+}
+
+// keyTTLs fetches the TTL of each key in one pipeline and sorts views by
+// time to expiry, soonest first. Keys without an expiry (or that vanished
+// meanwhile) go last, by name.
+func keyTTLs(ctx context.Context, views []KeyView) error {
+	if len(views) == 0 {
+		return nil
+	}
+	pipe := redisClient().Pipeline()
+	ttls := make([]*redis.DurationCmd, len(views))
+	for i, v := range views {
+		ttls[i] = pipe.PTTL(ctx, v.Name)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return backendErr("redis", err)
+	}
+	for i, c := range ttls {
+		// PTTL replies -1 (no expiry) or -2 (missing) as negative durations
+		views[i].TTL = c.Val()
+		if views[i].TTL < 0 {
+			views[i].TTL = -1
+		} else {
+			views[i].TTL = views[i].TTL.Round(time.Second)
+		}
+	}
+	sort.SliceStable(views, func(i, j int) bool {
+		a, b := views[i].TTL, views[j].TTL
+		if (a < 0) != (b < 0) {
+			return b < 0
+		}
+		if a < 0 {
+			return views[i].Name < views[j].Name
+		}
+		return a < b
+	})
+	return nil
+}
+
+// keyDetails fills in the type and size of each key using two pipelines:
+// TYPE for every key, then the size command matching each type.
+func keyDetails(ctx context.Context, views []KeyView) error {
+	if len(views) == 0 {
+		return nil
+	}
+	pipe := redisClient().Pipeline()
+	types := make([]*redis.StatusCmd, len(views))
+	for i, v := range views {
+		types[i] = pipe.Type(ctx, v.Name)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return backendErr("redis", err)
+	}
+
+	pipe = redisClient().Pipeline()
+	sizes := make([]*redis.IntCmd, len(views))
+	for i := range views {
+		views[i].Type = types[i].Val()
+		switch views[i].Type {
+		case "list":
+			sizes[i] = pipe.LLen(ctx, views[i].Name)
+		case "hash":
+			sizes[i] = pipe.HLen(ctx, views[i].Name)
+		case "set":
+			sizes[i] = pipe.SCard(ctx, views[i].Name)
+		case "zset":
+			sizes[i] = pipe.ZCard(ctx, views[i].Name)
+		case "stream":
+			sizes[i] = pipe.XLen(ctx, views[i].Name)
+		}
+	}
+	if pipe.Len() > 0 {
+		if _, err := pipe.Exec(ctx); err != nil {
+			return backendErr("redis", err)
+		}
+	}
+	for i, c := range sizes {
+		if c != nil {
+			views[i].Size = c.Val()
+		}
+	}
+	return nil
+}
+
+// encodeKey encodes a Redis key for the ?k= parameter of links. Keys may
+// hold any bytes, which base64url carries through URLs and HTML intact.
+func encodeKey(key string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(key))
+}
+
+// requestKey returns the Redis key named by ?k= (see encodeKey), falling
+// back to a raw ?key= for hand-written URLs.
+func requestKey(r *http.Request) string {
+	if k := r.FormValue("k"); k != "" {
+		if b, err := base64.RawURLEncoding.DecodeString(k); err == nil {
+			return string(b)
+		}
+	}
+	return r.FormValue("key")
+}
+
+// typeIcon is the key-list icon for a Redis type.
+func typeIcon(kt string) string {
+	switch kt {
+	case "string":
+		return "🔤"
+	case "list":
+		return "📜"
+	case "hash":
+		return "🗂"
+	case "set":
+		return "🔘"
+	case "zset":
+		return "🏅"
+	case "stream":
+		return "🌊"
+	}
+	return "❔"
+}
+
+// size of the preview shown for values above the configured thresholds
+const (
+	redisPreviewBytes = 64 * 1024
+	redisPreviewElems = 200
+)
+
+func redisKeyHandler(w http.ResponseWriter, r *http.Request) {
+	if redisClient() == nil {
+		content := `<div class="card"><h2>Redis Key</h2><p style="color:#6b7280">Redis not configured.</p></div>`
+		page := layout("Redis Key", content, backendStatus())
 		fmt.Fprint(w, page)
 		return
 	}
-	var docs []bson.M
-	if err := cur.All(ctx, &docs); err != nil {
-		content := `<div class="card"><h2>Collection: ` + template.HTMLEscapeString(name) + `</h2><p style="color:#6b7280">failed to read docs</p></div>`
-		page := layout("Collection", content)
+
+	key := requestKey(r)
+	if key == "" {
+		http.Error(w, "missing key param", 400)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), backendTimeout)
+	defer cancel()
+	kt, err := redisClient().Type(ctx, key).Result()
+	countBackendError("redis", err)
+	if isTimeout(err) {
+		renderTimeout(w, "Redis Key")
+		return
+	}
+	if err != nil {
+		content := `<div class="card"><h2>Redis Key</h2><p style="color:#6b7280">Failed to read key type: ` + template.HTMLEscapeString(err.Error()) + `</p></div>`
+		page := layout("Redis Key", content, backendStatus())
 		fmt.Fprint(w, page)
 		return
 	}
 
-	jb, _ := json.MarshalIndent(docs, "", "  ")
-	escaped := template.HTMLEscapeString(string(jb))
+	indent := jsonIndent(r)
+	sortBy := r.URL.Query().Get("sort")
+	win := elemWindow(r)
+	val, err := readRedisValue(ctx, key, kt, indent, sortBy, win)
+	if err != nil {
+		// a hot key may have been rewritten as another type between TYPE
+		// and the read (WRONGTYPE); re-check once and retry with the new type
+		if kt2, terr := redisClient().Type(ctx, key).Result(); backendErr("redis", terr) == nil && kt2 != kt {
+			kt = kt2
+			val, err = readRedisValue(ctx, key, kt, indent, sortBy, win)
+		}
+	}
+
+	if isTimeout(err) {
+		renderTimeout(w, "Redis Key")
+		return
+	}
+
+	notice := ""
+	if err != nil {
+		notice = fmt.Sprintf(`<p style="color:#b91c1c">Failed to read %s value: %s</p>`,
+			template.HTMLEscapeString(kt), template.HTMLEscapeString(err.Error()))
+	} else if val.Truncated {
+		notice = fmt.Sprintf(`<p style="color:#b45309">Value too large (%d %s) — showing a preview only. <a href="/redis-data/download?k=%s">Download full value</a></p>`,
+			val.Total, val.unit(kt), encodeKey(key))
+	}
+
+	// for collection types always show the real size, so a capped render
+	// is never mistaken for the whole value
+	summary := kt
+	if err == nil && kt != "string" && kt != "none" {
+		summary = fmt.Sprintf("%s · %d %s", kt, val.Total, val.unit(kt))
+		if int64(val.Shown) < val.Total {
+			if kt == "list" || kt == "zset" {
+				summary += fmt.Sprintf(", showing %d–%d", win.start+1, win.start+int64(val.Shown))
+			} else {
+				summary += fmt.Sprintf(", showing %d", val.Shown)
+			}
+		}
+	}
+	contentChip := ""
+	if val.Content != "" {
+		contentChip = ` <span class="chip" title="Detected content type">` + val.Content + `</span>`
+	}
 
 	content := fmt.Sprintf(`
 <div class="card">
-  <h2>📁 Collection: %s (sample %d rows)</h2>
+  <h2>🔑 Key: %s <span style="font-size:14px;color:#6b7280">(%s)</span>%s</h2>
+  %s
   <div style="margin-bottom:10px">
-    <button class="copy-btn" onclick="copyTextById('jsonData')">Copy JSON</button>
+    <button class="copy-btn" onclick="copyTextById('redisJson')">Copy</button>
+    <button class="copy-btn" onclick="copyViewLink()">🔗 Copy link</button>
+    %s
+    %s
   </div>
-  <pre id="jsonData" class="json">%s</pre>
+  %s
+  %s
+  <pre id="redisJson" class="json">%s</pre>
+  %s
+  %s
 </div>
-`, template.HTMLEscapeString(name), len(docs), escaped)
+`, template.HTMLEscapeString(key), template.HTMLEscapeString(summary), contentChip, notice, compactToggle(r), keyDeleteButton(key), keyMeta(ctx, key), sortLinks(r, kt), val.Body, valuePager(r, kt, val, win), hashFieldEditor(key, kt, val.Fields))
 
-	page := layout("Collection: "+name, content)
+	page := layout("Redis Key: "+key, content, backendStatus())
 	fmt.Fprint(w, page)
 }
 
-/////////////////////////////////////////////////////////////
-// Redis viewer
-/////////////////////////////////////////////////////////////
+// keyMeta renders the expiry and approximate memory footprint of a key.
+// MEMORY USAGE is often disabled on managed Redis; its failure only drops
+// the size.
+func keyMeta(ctx context.Context, key string) string {
+	pipe := redisClient().Pipeline()
+	ttl := pipe.TTL(ctx, key)
+	mem := pipe.MemoryUsage(ctx, key)
+	pipe.Exec(ctx)
 
-func redisDataHandler(w http.ResponseWriter, r *http.Request) {
-	if redisClient == nil {
-		content := `<div class="card"><h2>Redis Keys</h2><p style="color:#6b7280">Redis not configured or unreachable.</p></div>`
-		page := layout("Redis Keys", content)
-		fmt.Fprint(w, page)
-		return
+	var parts []string
+	// TTL replies -1 (no expiry) and -2 (missing) as negative durations
+	switch d, err := ttl.Result(); {
+	case err != nil:
+		parts = append(parts, "TTL unavailable")
+	case d == -2:
+		parts = append(parts, "missing (expired or deleted)")
+	case d < 0:
+		parts = append(parts, "persistent, no expiry")
+	default:
+		parts = append(parts, "expires in "+d.String())
+	}
+	if n, err := mem.Result(); err == nil {
+		parts = append(parts, "≈ "+humanBytes(n)+" in memory")
+	} else if err != redis.Nil {
+		slog.Error("redis memory usage failed", "backend", "redis", "key", key, "error", err)
+	}
+	return `<div style="margin-bottom:10px;color:#6b7280;font-size:13px">⏱ ` + template.HTMLEscapeString(strings.Join(parts, " · ")) + `</div>`
+}
+
+// redisValue is a key's value as read for display.
+type redisValue struct {
+	Body      string            // escaped, ready to render
+	Total     int64             // STRLEN for strings, element count otherwise
+	Shown     int               // elements actually read (collection types)
+	Truncated bool              // strings: above the configured threshold, only a preview was read
+	Fields    map[string]string // hash fields as shown (redacted), for editing
+	Content   string            // strings: "json" when pretty-printed, else "text"
+	Cursor    uint64            // large hashes and sets: SCAN cursor of the next page, 0 at the end
+}
+
+// valueWindow is the page of a collection value to read: lists and zsets by
+// index (?start=, ?count=), large hashes and sets by SCAN cursor (?cursor=).
+type valueWindow struct {
+	start, count int64
+	cursor       uint64
+}
+
+// elemWindow reads the page parameters of a key view. count defaults to
+// redisPreviewElems and is capped at limits.RedisMaxElements.
+func elemWindow(r *http.Request) valueWindow {
+	q := r.URL.Query()
+	var win valueWindow
+	win.start, _ = strconv.ParseInt(q.Get("start"), 10, 64)
+	win.start = max(win.start, 0)
+	win.count, _ = strconv.ParseInt(q.Get("count"), 10, 64)
+	if win.count < 1 {
+		win.count = redisPreviewElems
 	}
+	win.count = min(win.count, limits.RedisMaxElements)
+	win.cursor, _ = strconv.ParseUint(q.Get("cursor"), 10, 64)
+	return win
+}
 
-	ctx := context.Background()
-	var cursor uint64
-	var keys []string
+// isJSONDocument reports whether s is a JSON object or array; bare JSON
+// scalars like 42 or "x" are more useful shown as plain text.
+func isJSONDocument(s string) bool {
+	t := strings.TrimSpace(s)
+	return (strings.HasPrefix(t, "{") || strings.HasPrefix(t, "[")) && json.Valid([]byte(t))
+}
 
-	for {
-		k, c, err := redisClient.Scan(ctx, cursor, "*", 200).Result()
+// unit returns what Total counts for a key type.
+func (v redisValue) unit(kt string) string {
+	switch kt {
+	case "string":
+		return "bytes"
+	case "list":
+		return "elements"
+	case "hash":
+		return "fields"
+	default:
+		return "members"
+	}
+}
+
+// readRedisValue reads key as type kt. The size is checked before reading
+// so a huge value can't blow up the page; above the threshold only a preview
+// of a string is read, and collections are read a page at a time. Read errors (e.g. WRONGTYPE) are returned. Non-string values are
+// rendered as JSON with the given indent (see jsonIndent), reading the page
+// win and ordering it by sortBy (see sortMembers).
+func readRedisValue(ctx context.Context, key, kt, indent, sortBy string, win valueWindow) (redisValue, error) {
+	var rv redisValue
+	var v interface{}
+	var err error
+	switch kt {
+	case "string":
+		if rv.Total, err = redisClient().StrLen(ctx, key).Result(); err != nil {
+			return rv, backendErr("redis", err)
+		}
+		var sv string
+		if rv.Total > limits.RedisMaxValueBytes {
+			sv, err = redisClient().GetRange(ctx, key, 0, redisPreviewBytes-1).Result()
+			rv.Truncated = true
+		} else {
+			sv, err = redisClient().Get(ctx, key).Result()
+		}
 		if err != nil {
-			log.Printf("redis scan error: %v", err)
-			break
+			return rv, backendErr("redis", err)
+		}
+		sv = redactJSONString(sv)
+		rv.Content = "text"
+		if !rv.Truncated && isJSONDocument(sv) {
+			// lay JSON out like the other types, keeping its key order
+			var buf bytes.Buffer
+			if indent == "" {
+				err = json.Compact(&buf, []byte(sv))
+			} else {
+				err = json.Indent(&buf, []byte(strings.TrimSpace(sv)), "", indent)
+			}
+			if err == nil {
+				sv, rv.Content = buf.String(), "json"
+			}
+		}
+		rv.Body = template.HTMLEscapeString(sv)
+		return rv, nil
+	case "list":
+		if rv.Total, err = redisClient().LLen(ctx, key).Result(); err != nil {
+			return rv, backendErr("redis", err)
+		}
+		var l []string
+		if l, err = redisClient().LRange(ctx, key, win.start, win.start+win.count-1).Result(); err != nil {
+			return rv, backendErr("redis", err)
+		}
+		v, rv.Shown = l, len(l)
+	case "hash":
+		if rv.Total, err = redisClient().HLen(ctx, key).Result(); err != nil {
+			return rv, backendErr("redis", err)
+		}
+		var m map[string]string
+		if rv.Total > limits.RedisMaxElements {
+			kv, next, err := redisClient().HScan(ctx, key, win.cursor, "*", win.count).Result()
+			if err != nil {
+				return rv, backendErr("redis", err)
+			}
+			rv.Cursor = next
+			m = make(map[string]string, len(kv)/2)
+			for i := 0; i+1 < len(kv); i += 2 {
+				m[kv[i]] = kv[i+1]
+			}
+		} else if m, err = redisClient().HGetAll(ctx, key).Result(); err != nil {
+			return rv, backendErr("redis", err)
+		}
+		rv.Fields = redactHash(m)
+		v, rv.Shown = rv.Fields, len(m)
+	case "set":
+		if rv.Total, err = redisClient().SCard(ctx, key).Result(); err != nil {
+			return rv, backendErr("redis", err)
+		}
+		var members []string
+		if rv.Total > limits.RedisMaxElements {
+			if members, rv.Cursor, err = redisClient().SScan(ctx, key, win.cursor, "*", win.count).Result(); err != nil {
+				return rv, backendErr("redis", err)
+			}
+		} else if members, err = redisClient().SMembers(ctx, key).Result(); err != nil {
+			return rv, backendErr("redis", err)
 		}
-		keys = append(keys, k...)
-		cursor = c
-		if cursor == 0 {
-			break
+		v, rv.Shown = members, len(members)
+	case "zset":
+		if rv.Total, err = redisClient().ZCard(ctx, key).Result(); err != nil {
+			return rv, backendErr("redis", err)
 		}
-		if len(keys) >= 1000 {
-			keys = keys[:1000]
-			break
+		var z []redis.Z
+		if z, err = redisClient().ZRangeWithScores(ctx, key, win.start, win.start+win.count-1).Result(); err != nil {
+			return rv, backendErr("redis", err)
 		}
+		v, rv.Shown = z, len(z)
+	default:
+		rv.Body = "(type not handled or empty)"
+		return rv, nil
 	}
 
-	// content template that uses range over keys (strings)
-	content := `
-<div class="card">
-  <h2>⚡ Redis Keys</h2>
-  <div class="row">
-    <input id="redisSearch" class="search" placeholder="Search keys..." onkeyup="filterList('redisSearch','rItem')"/>
-  </div>
+	rv.Body = template.HTMLEscapeString(string(marshalView(sortMembers(v, sortBy), indent)))
+	return rv, nil
+}
 
-  <div class="list">
-    {{range .}}
-      <div class="list-item rItem">
-        <div><a href="/redis-data/key?key={{.}}">{{.}}</a></div>
-      </div>
-    {{end}}
-  </div>
-</div>
-`
+// valuePager renders Previous/Next links through a collection value too
+// large for one page: by index for lists and zsets, by SCAN cursor for
+// hashes and sets, which can only move forward or start over.
+func valuePager(r *http.Request, kt string, val redisValue, win valueWindow) string {
+	step := func(param string, to uint64, label string) string {
+		q := r.URL.Query()
+		if to == 0 {
+			q.Del(param)
+		} else {
+			q.Set(param, strconv.FormatUint(to, 10))
+		}
+		return `<a href="/redis-data/key?` + template.HTMLEscapeString(q.Encode()) + `">` + label + `</a>`
+	}
+	var links []string
+	switch kt {
+	case "list", "zset":
+		if win.start > 0 {
+			links = append(links, step("start", uint64(max(win.start-win.count, 0)), "← Previous"))
+		}
+		if win.start+int64(val.Shown) < val.Total {
+			links = append(links, step("start", uint64(win.start+int64(val.Shown)), "Next →"))
+		}
+	case "hash", "set":
+		if win.cursor != 0 {
+			links = append(links, step("cursor", 0, "↺ First page"))
+		}
+		if val.Cursor != 0 {
+			links = append(links, step("cursor", val.Cursor, "Next →"))
+		}
+	}
+	if len(links) == 0 {
+		return ""
+	}
+	return `<div class="row" style="justify-content:center;margin-top:12px">` + strings.Join(links, " · ") + `</div>`
+}
 
-	tpl := template.Must(template.New("redis").Parse(layout("Redis Keys", content)))
-	tpl.Execute(w, keys)
+// memberSorts are the ?sort= orders offered per type. Hashes need none:
+// they render as a JSON object, which is always ordered by field name.
+var memberSorts = map[string][]string{
+	"set":  {"member"},
+	"zset": {"score", "-score", "member"},
 }
 
-func redisKeyHandler(w http.ResponseWriter, r *http.Request) {
-	if redisClient == nil {
-		content := `<div class="card"><h2>Redis Key</h2><p style="color:#6b7280">Redis not configured.</p></div>`
-		page := layout("Redis Key", content)
-		fmt.Fprint(w, page)
+// sortMembers orders the read window of a set (by member) or zset (by
+// score, descending score or member). Only what was read is sorted, so a
+// preview is the first window in Redis order, sorted.
+func sortMembers(v interface{}, by string) interface{} {
+	switch t := v.(type) {
+	case []string:
+		if by == "member" {
+			sort.Strings(t)
+		}
+	case []redis.Z:
+		switch by {
+		case "score":
+			sort.SliceStable(t, func(i, j int) bool { return t[i].Score < t[j].Score })
+		case "-score":
+			sort.SliceStable(t, func(i, j int) bool { return t[i].Score > t[j].Score })
+		case "member":
+			sort.SliceStable(t, func(i, j int) bool { return fmt.Sprint(t[i].Member) < fmt.Sprint(t[j].Member) })
+		}
+	}
+	return v
+}
+
+// sortLinks renders the ?sort= choices for a key type, keeping the rest of
+// the query.
+func sortLinks(r *http.Request, kt string) string {
+	opts := memberSorts[kt]
+	if len(opts) == 0 {
+		return ""
+	}
+	current := r.URL.Query().Get("sort")
+	var links []string
+	for _, o := range append([]string{""}, opts...) {
+		label := map[string]string{"": "redis order", "score": "score ↑", "-score": "score ↓"}[o]
+		if label == "" {
+			label = o
+		}
+		if o == current {
+			links = append(links, "<b>"+label+"</b>")
+			continue
+		}
+		q := r.URL.Query()
+		if o == "" {
+			q.Del("sort")
+		} else {
+			q.Set("sort", o)
+		}
+		links = append(links, `<a href="/redis-data/key?`+template.HTMLEscapeString(q.Encode())+`">`+label+`</a>`)
+	}
+	return `<div style="margin-bottom:10px;color:#6b7280;font-size:13px">Sort: ` + strings.Join(links, " · ") + `</div>`
+}
+
+// redisDownloadHandler sends the full value of a key as an attachment, for
+// values too large to render. Strings are sent raw, other types as JSON.
+func redisDownloadHandler(w http.ResponseWriter, r *http.Request) {
+	if redisClient() == nil {
+		http.Error(w, "redis not configured", 503)
 		return
 	}
 
-	key := r.URL.Query().Get("key")
+	key := requestKey(r)
 	if key == "" {
 		http.Error(w, "missing key param", 400)
 		return
 	}
 
-	ctx := context.Background()
-	kt, _ := redisClient.Type(ctx, key).Result()
-	var body string
+	ctx, cancel := context.WithTimeout(r.Context(), backendTimeout)
+	defer cancel()
+	kt, err := redisClient().Type(ctx, key).Result()
+	countBackendError("redis", err)
+	var v interface{}
 	switch kt {
 	case "string":
-		v, _ := redisClient.Get(ctx, key).Result()
-		body = template.HTMLEscapeString(v)
+		var b []byte
+		b, err = redisClient().Get(ctx, key).Bytes()
+		if err != nil {
+			countBackendError("redis", err)
+			http.Error(w, err.Error(), 500)
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "redis-value.bin"))
+		w.Write([]byte(redactJSONString(string(b))))
+		return
 	case "list":
-		v, _ := redisClient.LRange(ctx, key, 0, 200).Result()
-		bs, _ := json.MarshalIndent(v, "", "  ")
-		body = template.HTMLEscapeString(string(bs))
+		v, err = redisClient().LRange(ctx, key, 0, -1).Result()
 	case "hash":
-		v, _ := redisClient.HGetAll(ctx, key).Result()
-		bs, _ := json.MarshalIndent(v, "", "  ")
-		body = template.HTMLEscapeString(string(bs))
+		var m map[string]string
+		m, err = redisClient().HGetAll(ctx, key).Result()
+		v = redactHash(m)
 	case "set":
-		v, _ := redisClient.SMembers(ctx, key).Result()
-		bs, _ := json.MarshalIndent(v, "", "  ")
-		body = template.HTMLEscapeString(string(bs))
+		v, err = redisClient().SMembers(ctx, key).Result()
 	case "zset":
-		v, _ := redisClient.ZRangeWithScores(ctx, key, 0, 200).Result()
-		bs, _ := json.MarshalIndent(v, "", "  ")
-		body = template.HTMLEscapeString(string(bs))
+		v, err = redisClient().ZRangeWithScores(ctx, key, 0, -1).Result()
 	default:
-		body = "(type not handled or empty)"
+		http.Error(w, "type not handled or key missing", 404)
+		return
+	}
+	if err != nil {
+		countBackendError("redis", err)
+		http.Error(w, err.Error(), 500)
+		return
 	}
 
-	content := fmt.Sprintf(`
-<div class="card">
-  <h2>🔑 Key: %s</h2>
-  <div style="margin-bottom:10px">
-    <button class="copy-btn" onclick="copyTextById('redisJson')">Copy</button>
-  </div>
-  <pre id="redisJson" class="json">%s</pre>
-</div>
-`, template.HTMLEscapeString(key), body)
-
-	page := layout("Redis Key: "+key, content)
-	fmt.Fprint(w, page)
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "redis-value.json"))
+	json.NewEncoder(w).Encode(v)
 }
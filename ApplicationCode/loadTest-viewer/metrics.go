@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/aws/smithy-go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Prometheus collectors, registered once by registerMetrics and served on
+// /metrics.
+var (
+	metricsRegistry = prometheus.NewRegistry()
+
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "loadtest_viewer_request_duration_seconds",
+		Help:    "Duration of HTTP requests by route.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"path"})
+
+	backendErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "loadtest_viewer_backend_errors_total",
+		Help: "Backend failures by backend (s3, mongo, redis).",
+	}, []string{"backend"})
+
+	reportsListed = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "loadtest_viewer_reports_listed",
+		Help: "Reports returned by the latest S3 listing.",
+	})
+
+	redisKeysScanned = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "loadtest_viewer_redis_keys_scanned",
+		Help: "Keys returned by the latest Redis key scan.",
+	})
+)
+
+func registerMetrics() {
+	metricsRegistry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		requestDuration, backendErrors, reportsListed, redisKeysScanned,
+	)
+	// pre-create the series so they read 0 rather than being absent
+	for _, b := range []string{"s3", "mongo", "redis"} {
+		backendErrors.WithLabelValues(b)
+	}
+}
+
+func metricsHandler() http.Handler {
+	return promhttp.HandlerFor(metricsRegistry, promhttp.HandlerOpts{})
+}
+
+// instrument times each request under the mux pattern it routes to, which
+// keeps the path label bounded (share tokens, unknown URLs). /metrics and
+// streaming paths aren't timed.
+func instrument(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/metrics" || streamingPaths[r.URL.Path] {
+			mux.ServeHTTP(w, r)
+			return
+		}
+		_, pattern := mux.Handler(r)
+		if pattern == "" {
+			pattern = "unmatched"
+		}
+		start := time.Now()
+		mux.ServeHTTP(w, r)
+		requestDuration.WithLabelValues(pattern).Observe(time.Since(start).Seconds())
+	})
+}
+
+// countBackendError counts err against backend ("s3", "mongo" or "redis")
+// for /metrics. Call it right after every backend call, so failures are
+// counted whether or not anything logs them. A missing Redis key, Mongo
+// document or S3 object is an answer rather than a failure, and a cancelled
+// request isn't the backend's fault; neither is counted.
+func countBackendError(backend string, err error) {
+	if err == nil || errors.Is(err, redis.Nil) || errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, context.Canceled) {
+		return
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && (apiErr.ErrorCode() == "NotFound" || apiErr.ErrorCode() == "NoSuchKey") {
+		return
+	}
+	backendErrors.WithLabelValues(backend).Inc()
+}
+
+// backendErr is countBackendError for call sites that return the error.
+func backendErr(backend string, err error) error {
+	countBackendError(backend, err)
+	return err
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"html/template"
+	"log/slog"
+	"net/http"
+)
+
+// pageSources are the template pages: a title and the content, both
+// template text over the page's data, e.g. "Watch: {{.Name}}".
+var pageSources = map[string]struct{ title, content string }{
+	"reports":     {"Load Test Reports", reportsPage},
+	"search":      {"Report Search", searchPage},
+	"db":          {"MongoDB Collections", collectionsPage},
+	"dbs":         {"MongoDB Databases", databasesPage},
+	"watch":       {"Watch: {{.Name}}", watchPage},
+	"validate":    {"Validate: {{.Name}}", validatePage},
+	"redis":       {"Redis Keys", redisKeysPage},
+	"redissearch": {"Search Redis values", redisSearchPage},
+	"inspect":     {"Inspect", inspectPage},
+}
+
+// pages holds pageSources parsed into the layout by parsePages.
+var pages map[string]*template.Template
+
+// pageFuncs are available to every page. sidebar renders the viewer links
+// with the backend status at execute time, the only part of the layout
+// that changes between requests.
+var pageFuncs = template.FuncMap{
+	"highlight": highlight,
+	"typeIcon":  typeIcon,
+	"bytes":     humanBytes,
+	"encodeKey": encodeKey,
+	"sidebar": func() template.HTML {
+		return template.HTML(withBasePath(navLinks(backendStatus())))
+	},
+}
+
+// pageLayout is layout with template text for the title and the sidebar
+// left to execute time.
+func pageLayout(title, content string) string {
+	return shell(title, content, "{{sidebar}}")
+}
+
+// parsePages parses every page once. A page that doesn't parse is a bug,
+// so startup fails.
+func parsePages() {
+	pages = make(map[string]*template.Template, len(pageSources))
+	for name := range pageSources {
+		pages[name] = template.Must(parsePage(name))
+	}
+}
+
+// parsePage parses one entry of pageSources inside the layout.
+func parsePage(name string) (*template.Template, error) {
+	src := pageSources[name]
+	return template.New(name).Funcs(pageFuncs).Parse(pageLayout(src.title, src.content))
+}
+
+// renderPage executes the named page with data.
+func renderPage(w http.ResponseWriter, name string, data interface{}) {
+	if err := pages[name].Execute(w, data); err != nil {
+		slog.Error("render failed", "page", name, "error", err)
+	}
+}
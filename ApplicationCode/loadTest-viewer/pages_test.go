@@ -0,0 +1,13 @@
+package main
+
+import "testing"
+
+func TestPagesParse(t *testing.T) {
+	for name := range pageSources {
+		t.Run(name, func(t *testing.T) {
+			if _, err := parsePage(name); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// purgeCaches drops every cached presigned URL, object tag set and
+// collection sample. Presigned URLs are already re-signed when a listed
+// object's LastModified changes; this covers everything else, e.g. links
+// handed out just before a report was overwritten.
+func purgeCaches() {
+	reportURLs.clear()
+	reportTags.clear()
+	if samples != nil {
+		samples.clear()
+	}
+}
+
+// cacheRefreshHandler purges the caches on POST and redirects back to the
+// page the form was on (?back=, a local path). Like every route it sits
+// behind UI_USER/UI_PASS, and the form must carry the CSRF token.
+func cacheRefreshHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !parseForm(w, r) {
+		return
+	}
+	if !checkCSRF(r) {
+		http.Error(w, "invalid or missing CSRF token — reload the page and try again", http.StatusForbidden)
+		return
+	}
+	purgeCaches()
+	slog.Info("caches purged", "remote_addr", r.RemoteAddr)
+
+	// only redirect within the viewer
+	back := r.PostForm.Get("back")
+	if !strings.HasPrefix(back, "/") || strings.HasPrefix(back, "//") || strings.HasPrefix(back, "/\\") {
+		back = "/load-test"
+	}
+	http.Redirect(w, r, basePath+back, http.StatusSeeOther)
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/json"
+	"path"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const redactedValue = "***"
+
+// redactFields are the lowercase field names or glob patterns (path.Match
+// syntax, e.g. "*token*") from REDACT_FIELDS whose values are masked in the
+// Mongo and Redis viewers.
+var redactFields []string
+
+func loadRedactFields(raw string) {
+	for _, f := range strings.Split(raw, ",") {
+		if f = strings.ToLower(strings.TrimSpace(f)); f != "" {
+			redactFields = append(redactFields, f)
+		}
+	}
+}
+
+// redactedField reports whether values of the field name must be masked.
+func redactedField(name string) bool {
+	name = strings.ToLower(name)
+	for _, p := range redactFields {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// redactedPath reports whether any segment of a dotted field path is
+// redacted, e.g. "user.password".
+func redactedPath(field string) bool {
+	for _, seg := range strings.Split(field, ".") {
+		if redactedField(seg) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactDocs returns docs with redacted field values masked at any depth.
+// Documents are copied rather than modified, since they may be shared with
+// the sample cache.
+func redactDocs(docs []bson.M) []bson.M {
+	if len(redactFields) == 0 {
+		return docs
+	}
+	out := make([]bson.M, len(docs))
+	for i, d := range docs {
+		out[i] = redactDoc(d)
+	}
+	return out
+}
+
+// redactDoc is redactDocs for a single document.
+func redactDoc(doc bson.M) bson.M {
+	if len(redactFields) == 0 {
+		return doc
+	}
+	return redactValue(doc).(bson.M)
+}
+
+// redactValue returns a copy of v with redacted fields masked, walking
+// nested documents and arrays.
+func redactValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case bson.M:
+		m := make(bson.M, len(t))
+		for k, fv := range t {
+			m[k] = redactEntry(k, fv)
+		}
+		return m
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, fv := range t {
+			m[k] = redactEntry(k, fv)
+		}
+		return m
+	case bson.D:
+		d := make(bson.D, len(t))
+		for i, e := range t {
+			d[i] = bson.E{Key: e.Key, Value: redactEntry(e.Key, e.Value)}
+		}
+		return d
+	case bson.A:
+		a := make(bson.A, len(t))
+		for i, e := range t {
+			a[i] = redactValue(e)
+		}
+		return a
+	case []interface{}:
+		a := make([]interface{}, len(t))
+		for i, e := range t {
+			a[i] = redactValue(e)
+		}
+		return a
+	}
+	return v
+}
+
+// redactEntry masks v when key names a redacted field. Keys may be dotted
+// paths, as in a change event's updatedFields.
+func redactEntry(key string, v interface{}) interface{} {
+	if redactedPath(key) {
+		return redactedValue
+	}
+	return redactValue(v)
+}
+
+// redactHash masks the redacted fields of a Redis hash.
+func redactHash(m map[string]string) map[string]string {
+	if len(redactFields) == 0 {
+		return m
+	}
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		if redactedField(k) {
+			v = redactedValue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// redactJSONString masks redacted fields in a Redis string holding a JSON
+// object or array. Other strings are returned unchanged.
+func redactJSONString(s string) string {
+	if len(redactFields) == 0 {
+		return s
+	}
+	trimmed := strings.TrimSpace(s)
+	if !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") {
+		return s
+	}
+	var v interface{}
+	if json.Unmarshal([]byte(trimmed), &v) != nil {
+		return s
+	}
+	// keep the original formatting unless something was masked
+	orig, _ := json.Marshal(v)
+	masked, err := json.Marshal(redactValue(v))
+	if err != nil || string(orig) == string(masked) {
+		return s
+	}
+	return string(masked)
+}
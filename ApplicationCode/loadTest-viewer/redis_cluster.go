@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisCluster is set when REDIS_CLUSTER=true or REDIS_URL lists several
+// comma-separated addresses; redisClient is then a *redis.ClusterClient.
+var redisCluster bool
+
+// newRedisClient builds the client for REDIS_URL, a cluster client when
+// redisCluster is set. poolSize > 0 caps the pool, per node for a cluster.
+func newRedisClient(redisURL string, poolSize int) redis.UniversalClient {
+	if redisCluster {
+		opt := clusterOptions(redisURL)
+		opt.ClientName = appName
+		if poolSize > 0 {
+			opt.PoolSize = poolSize
+		}
+		return redis.NewClusterClient(opt)
+	}
+	opt, err := redis.ParseURL(redisURL)
+	if err != nil {
+		opt = &redis.Options{Addr: redisURL}
+	}
+	opt.ClientName = appName
+	if poolSize > 0 {
+		opt.PoolSize = poolSize
+	}
+	return redis.NewClient(opt)
+}
+
+// clusterOptions reads the seed nodes of a cluster from REDIS_URL: either
+// a comma-separated list of URLs or host:port addresses, the first URL
+// supplying credentials and TLS, or one URL with ?addr= for further seeds.
+func clusterOptions(redisURL string) *redis.ClusterOptions {
+	if !strings.Contains(redisURL, ",") {
+		if opt, err := redis.ParseClusterURL(redisURL); err == nil {
+			return opt
+		}
+		return &redis.ClusterOptions{Addrs: []string{redisURL}}
+	}
+	opt := &redis.ClusterOptions{}
+	authSet := false
+	for _, part := range strings.Split(redisURL, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		single, err := redis.ParseURL(part)
+		if err != nil {
+			opt.Addrs = append(opt.Addrs, part)
+			continue
+		}
+		opt.Addrs = append(opt.Addrs, single.Addr)
+		if !authSet {
+			opt.Username, opt.Password, opt.TLSConfig = single.Username, single.Password, single.TLSConfig
+			authSet = true
+		}
+	}
+	return opt
+}
+
+// redisScanNodes returns the clients a SCAN has to walk to see every key:
+// each master of a cluster, which only scans its own shard, in address
+// order, or just redisClient.
+func redisScanNodes(ctx context.Context) ([]redis.UniversalClient, error) {
+	cc, ok := redisClient().(*redis.ClusterClient)
+	if !ok {
+		return []redis.UniversalClient{redisClient()}, nil
+	}
+	var mu sync.Mutex
+	var masters []*redis.Client
+	err := cc.ForEachMaster(ctx, func(ctx context.Context, c *redis.Client) error {
+		mu.Lock()
+		masters = append(masters, c)
+		mu.Unlock()
+		return nil
+	})
+	sort.Slice(masters, func(i, j int) bool { return masters[i].Options().Addr < masters[j].Options().Addr })
+	nodes := make([]redis.UniversalClient, len(masters))
+	for i, c := range masters {
+		nodes[i] = c
+	}
+	return nodes, backendErr("redis", err)
+}
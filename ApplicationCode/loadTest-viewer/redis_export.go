@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// redisExportEntry is one key in an export snapshot. TTL is in seconds and
+// -1 for keys without an expiry.
+type redisExportEntry struct {
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+	TTL   int64       `json:"ttl"`
+}
+
+// redisExportTimeout bounds an export (REDIS_EXPORT_TIMEOUT). The export is
+// a streaming path without the handler deadline, so this is its only bound.
+var redisExportTimeout = 5 * time.Minute
+
+// redisExportHandler streams every key matching ?match= as one JSON object
+// of {key: {type, value, ttl}}, so a namespace can be snapshotted and
+// diffed. At most limits.RedisExportMaxKeys keys are written; keys are
+// fetched one at a time so the keyspace is never held in memory. An export
+// that stops early ends with a "_truncated" or "_error" member saying why.
+func redisExportHandler(w http.ResponseWriter, r *http.Request) {
+	if redisClient() == nil {
+		http.Error(w, "redis not configured", 503)
+		return
+	}
+	match := r.URL.Query().Get("match")
+	if match == "" {
+		match = redisDefaultMatch
+	}
+	if len(match) > maxMatchLen {
+		http.Error(w, "match pattern too long", 400)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), redisExportTimeout)
+	defer cancel()
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "redis-export-"+time.Now().UTC().Format("20060102-150405")+".json"))
+	ex := newExportWriter(w)
+	ex.close(exportKeys(ctx, ex, match))
+}
+
+// exportKeys writes the keys matching match to ex. It returns the marker
+// and reason to end the export with, or "" when every key was written.
+func exportKeys(ctx context.Context, ex *exportWriter, match string) (marker, reason string) {
+	nodes, err := redisScanNodes(ctx)
+	if err != nil {
+		slog.Error("redis export: cluster nodes failed", "backend", "redis", "error", err)
+		return "_error", "listing cluster nodes failed: " + err.Error()
+	}
+	// every master of a cluster holds its own share of the keys
+	for _, node := range nodes {
+		var cursor uint64
+		for {
+			keys, next, err := node.Scan(ctx, cursor, match, 200).Result()
+			countBackendError("redis", err)
+			if err != nil {
+				slog.Error("redis export: scan failed", "backend", "redis", "match", match, "error", err)
+				return "_error", "scan failed after " + strconv.Itoa(ex.written) + " keys: " + err.Error()
+			}
+			for _, key := range keys {
+				if int64(ex.written) >= limits.RedisExportMaxKeys {
+					slog.Warn("redis export: key limit reached", "keys", ex.written, "match", match)
+					return "_truncated", fmt.Sprintf("stopped at the limit of %d keys (REDIS_EXPORT_MAX_KEYS)", limits.RedisExportMaxKeys)
+				}
+				entry, err := exportRedisKey(ctx, key)
+				if ctx.Err() != nil {
+					slog.Error("redis export: stopped", "backend", "redis", "match", match, "keys", ex.written, "error", ctx.Err())
+					return "_error", "stopped after " + strconv.Itoa(ex.written) + " keys: " + ctx.Err().Error()
+				}
+				if err != nil {
+					// expired or deleted since the scan
+					slog.Warn("redis export: key skipped", "backend", "redis", "key", key, "error", err)
+					continue
+				}
+				ex.entry(key, entry)
+			}
+			ex.flush()
+			if cursor = next; cursor == 0 {
+				break
+			}
+		}
+	}
+	return "", ""
+}
+
+// exportWriter writes the members of an export object as they come.
+type exportWriter struct {
+	w       io.Writer
+	written int // keys written
+}
+
+func newExportWriter(w io.Writer) *exportWriter {
+	io.WriteString(w, "{")
+	return &exportWriter{w: w}
+}
+
+func (e *exportWriter) member(name string, v interface{}) error {
+	k, _ := json.Marshal(name)
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	sep := ","
+	if e.written == 0 {
+		sep = ""
+	}
+	fmt.Fprintf(e.w, "%s\n%s:%s", sep, k, b)
+	return nil
+}
+
+func (e *exportWriter) entry(key string, v redisExportEntry) {
+	if e.member(key, v) == nil {
+		e.written++
+	}
+}
+
+func (e *exportWriter) flush() {
+	if f, ok := e.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// close ends the object, first adding marker: reason when the export
+// stopped early.
+func (e *exportWriter) close(marker, reason string) {
+	if marker != "" {
+		e.member(marker, reason)
+	}
+	io.WriteString(e.w, "\n}\n")
+}
+
+// exportRedisKey reads the full value and TTL of key, masking redacted
+// fields as the viewer does.
+func exportRedisKey(ctx context.Context, key string) (redisExportEntry, error) {
+	var e redisExportEntry
+	var err error
+	if e.Type, err = redisClient().Type(ctx, key).Result(); err != nil {
+		return e, backendErr("redis", err)
+	}
+	switch e.Type {
+	case "string":
+		var s string
+		s, err = redisClient().Get(ctx, key).Result()
+		e.Value = redactJSONString(s)
+	case "list":
+		e.Value, err = redisClient().LRange(ctx, key, 0, -1).Result()
+	case "hash":
+		var m map[string]string
+		m, err = redisClient().HGetAll(ctx, key).Result()
+		e.Value = redactHash(m)
+	case "set":
+		e.Value, err = redisClient().SMembers(ctx, key).Result()
+	case "zset":
+		e.Value, err = redisClient().ZRangeWithScores(ctx, key, 0, -1).Result()
+	case "none":
+		return e, fmt.Errorf("key no longer exists")
+	default:
+		return e, fmt.Errorf("type %s not exported", e.Type)
+	}
+	if err != nil {
+		return e, backendErr("redis", err)
+	}
+	ttl, err := redisClient().TTL(ctx, key).Result()
+	if err != nil {
+		return e, backendErr("redis", err)
+	}
+	e.TTL = -1
+	if ttl > 0 {
+		e.TTL = int64(ttl / time.Second)
+	}
+	return e, nil
+}
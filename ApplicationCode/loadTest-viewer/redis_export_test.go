@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestExportWriterMarkers(t *testing.T) {
+	tests := []struct {
+		name           string
+		keys           int
+		marker, reason string
+	}{
+		{"complete", 2, "", ""},
+		{"truncated", 2, "_truncated", "stopped at the limit of 2 keys"},
+		{"error before any key", 0, "_error", "scan failed after 0 keys"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			ex := newExportWriter(&b)
+			for i := 0; i < tt.keys; i++ {
+				ex.entry("k"+string(rune('a'+i)), redisExportEntry{Type: "string", Value: "v", TTL: -1})
+			}
+			ex.close(tt.marker, tt.reason)
+
+			var got map[string]json.RawMessage
+			if err := json.Unmarshal([]byte(b.String()), &got); err != nil {
+				t.Fatalf("export is not valid JSON: %v\n%s", err, b.String())
+			}
+			if n := len(got); tt.marker == "" && n != tt.keys || tt.marker != "" && n != tt.keys+1 {
+				t.Errorf("got %d members, want %d keys plus marker %q", n, tt.keys, tt.marker)
+			}
+			if tt.marker != "" {
+				var reason string
+				json.Unmarshal(got[tt.marker], &reason)
+				if reason != tt.reason {
+					t.Errorf("%s = %q, want %q", tt.marker, reason, tt.reason)
+				}
+			}
+		})
+	}
+}
+
+func TestRedisExportHandlerErrors(t *testing.T) {
+	// nothing listens on port 1, so the scan fails straight away
+	rdb := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	defer rdb.Close()
+	setRedisClient(rdb)
+	defer setRedisClient(nil)
+
+	w := httptest.NewRecorder()
+	redisExportHandler(w, httptest.NewRequest("GET", "/redis-data/export?match=app:*", nil))
+	var got map[string]string
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("export is not valid JSON: %v\n%s", err, w.Body.String())
+	}
+	if !strings.HasPrefix(got["_error"], "scan failed after 0 keys") {
+		t.Errorf("a failed scan should end with an _error member, got %s", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	long := url.QueryEscape(strings.Repeat("x", maxMatchLen+1))
+	redisExportHandler(w, httptest.NewRequest("GET", "/redis-data/export?match="+long, nil))
+	if w.Code != 400 {
+		t.Errorf("match longer than maxMatchLen: got %d, want 400", w.Code)
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisSearchTimeout bounds a value search (REDIS_SEARCH_TIMEOUT); it
+// still can't outlive the handler deadline.
+var redisSearchTimeout = 30 * time.Second
+
+// redisSearchBatch is how many keys are typed and read per pipeline.
+const redisSearchBatch = 100
+
+// ValueMatch is a key whose value contains the search term.
+type ValueMatch struct {
+	Key  string
+	Type string
+}
+
+// redisSearchPage renders the value search.
+const redisSearchPage = `
+<div class="card">
+  <h2>🔍 Search Redis values</h2>
+  <p style="color:#b45309">This reads the value of every key matching the pattern, up to {{.MaxKeys}} keys — a full scan that may be slow and loads Redis. Narrow it with a MATCH pattern where you can.</p>
+  <form method="get" class="row">
+    <input name="q" value="{{.Q}}" class="search" placeholder="Text to find in values (case-insensitive)" required/>
+    <input name="match" value="{{.Match}}" class="search" style="max-width:180px" title="SCAN MATCH pattern" placeholder="MATCH pattern"/>
+    <button class="copy-btn" type="submit">Search</button>
+    <a href="/redis-data?match={{.Match}}" style="white-space:nowrap">← Keys</a>
+  </form>
+  {{if .Problem}}<p style="color:#b91c1c">{{.Problem}}</p>{{end}}
+  {{if .Q}}
+  <div style="margin:8px 0;color:#6b7280;font-size:13px">
+    {{len .Matches}} of {{.Scanned}} keys scanned match · {{.Took}}{{if .Capped}} · stopped at the {{.MaxKeys}} key cap{{end}}{{if .TimedOut}} · timed out, results are partial{{end}}
+  </div>
+  <div class="list">
+  {{range .Matches}}
+    <div class="list-item">
+      <div>{{typeIcon .Type}} <a href="/redis-data/key?k={{encodeKey .Key}}">{{.Key}}</a></div>
+      <div class="badge">{{.Type}}</div>
+    </div>
+  {{end}}
+  </div>
+  {{end}}
+</div>
+`
+
+// redisSearchHandler finds the keys whose value contains ?q=, among the
+// keys matching ?match=. Values are matched as the viewer shows them, so
+// redacted fields are never searched, and capped like the key view
+// (limits.RedisMaxValueBytes, limits.RedisMaxElements).
+func redisSearchHandler(w http.ResponseWriter, r *http.Request) {
+	if redisClient() == nil {
+		http.Error(w, "redis not configured", 503)
+		return
+	}
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	match := r.URL.Query().Get("match")
+	if match == "" {
+		match = redisDefaultMatch
+	}
+	if len(match) > maxMatchLen {
+		http.Error(w, "match pattern too long", 400)
+		return
+	}
+
+	data := map[string]interface{}{
+		"Q":       q,
+		"Match":   match,
+		"MaxKeys": limits.RedisSearchMaxKeys,
+	}
+	if q != "" {
+		ctx, cancel := context.WithTimeout(r.Context(), redisSearchTimeout)
+		defer cancel()
+		start := time.Now()
+		keys, err := scanRedisKeys(ctx, match, "", int(limits.RedisSearchMaxKeys))
+		var matches []ValueMatch
+		scanned := 0
+		if err == nil {
+			matches, scanned, err = searchRedisValues(ctx, keys, q)
+		}
+		data["Matches"] = matches
+		data["Scanned"] = scanned
+		data["Capped"] = int64(len(keys)) >= limits.RedisSearchMaxKeys
+		data["Took"] = time.Since(start).Round(time.Millisecond).String()
+		switch {
+		case isTimeout(err):
+			data["TimedOut"] = true
+		case err != nil:
+			data["Problem"] = "Search failed: " + err.Error()
+		}
+	}
+	renderPage(w, "redissearch", data)
+}
+
+// searchRedisValues reads keys in pipelined batches and returns those whose
+// value contains q, and how many keys were read before ctx ran out.
+func searchRedisValues(ctx context.Context, keys []string, q string) ([]ValueMatch, int, error) {
+	var out []ValueMatch
+	for i := 0; i < len(keys); i += redisSearchBatch {
+		batch := keys[i:min(i+redisSearchBatch, len(keys))]
+
+		pipe := redisClient().Pipeline()
+		types := make([]*redis.StatusCmd, len(batch))
+		for j, k := range batch {
+			types[j] = pipe.Type(ctx, k)
+		}
+		if _, err := pipe.Exec(ctx); err != nil && !isReplyError(err) {
+			return out, i, backendErr("redis", err)
+		}
+
+		pipe = redisClient().Pipeline()
+		reads := make([]func() string, len(batch))
+		for j, k := range batch {
+			reads[j] = queueValueText(ctx, pipe, k, types[j].Val())
+		}
+		if _, err := pipe.Exec(ctx); err != nil && !isReplyError(err) {
+			return out, i, backendErr("redis", err)
+		}
+		for j, k := range batch {
+			if reads[j] != nil && matchesQuery(reads[j](), q) {
+				out = append(out, ValueMatch{Key: k, Type: types[j].Val()})
+			}
+		}
+	}
+	return out, len(keys), nil
+}
+
+// isReplyError reports whether err is an error reply from Redis (including
+// redis.Nil) for a single command, such as WRONGTYPE for a key rewritten
+// since it was typed, rather than a failure of the connection.
+func isReplyError(err error) bool {
+	var re redis.Error
+	return errors.As(err, &re)
+}
+
+// queueValueText queues the read of key (of type kt) on pipe and returns a
+// function giving the value as searchable text once the pipe has run, or
+// nil for types that aren't searched.
+func queueValueText(ctx context.Context, pipe redis.Pipeliner, key, kt string) func() string {
+	n := limits.RedisMaxElements
+	switch kt {
+	case "string":
+		c := pipe.GetRange(ctx, key, 0, limits.RedisMaxValueBytes-1)
+		return func() string { return redactJSONString(c.Val()) }
+	case "list":
+		c := pipe.LRange(ctx, key, 0, n-1)
+		return func() string { return strings.Join(c.Val(), "\n") }
+	case "hash":
+		c := pipe.HScan(ctx, key, 0, "*", n)
+		return func() string {
+			kv, _ := c.Val()
+			m := make(map[string]string, len(kv)/2)
+			for i := 0; i+1 < len(kv); i += 2 {
+				m[kv[i]] = kv[i+1]
+			}
+			var b strings.Builder
+			for f, v := range redactHash(m) {
+				b.WriteString(f + "\n" + v + "\n")
+			}
+			return b.String()
+		}
+	case "set":
+		c := pipe.SScan(ctx, key, 0, "*", n)
+		return func() string {
+			members, _ := c.Val()
+			return strings.Join(members, "\n")
+		}
+	case "zset":
+		c := pipe.ZRange(ctx, key, 0, n-1)
+		return func() string { return strings.Join(c.Val(), "\n") }
+	}
+	return nil
+}
@@ -0,0 +1,412 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisCreateHandler shows a form to create a key of any type and, on POST,
+// creates it from the submitted JSON. Existing keys are never overwritten.
+// Only available with ALLOW_WRITES=true, and only for forms carrying
+// the CSRF token.
+func redisCreateHandler(w http.ResponseWriter, r *http.Request) {
+	if redisClient() == nil {
+		content := `<div class="card"><h2>New Redis Key</h2><p style="color:#6b7280">Redis not configured.</p></div>`
+		page := layout("New Redis Key", content, backendStatus())
+		fmt.Fprint(w, page)
+		return
+	}
+	if !redisWrite {
+		content := `<div class="card"><h2>New Redis Key</h2><p style="color:#6b7280">Redis writes are disabled. Set <code>ALLOW_WRITES=true</code> to enable them.</p></div>`
+		page := layout("New Redis Key", content, backendStatus())
+		fmt.Fprint(w, page)
+		return
+	}
+	if !parseForm(w, r) {
+		return
+	}
+
+	key, kt, value := r.FormValue("key"), r.FormValue("type"), r.FormValue("value")
+	notice := ""
+	if r.Method == http.MethodPost {
+		if !checkCSRF(r) {
+			http.Error(w, "invalid or missing CSRF token — reload the page and try again", http.StatusForbidden)
+			return
+		}
+		ctx, cancel := context.WithTimeout(r.Context(), backendTimeout)
+		defer cancel()
+		err := createRedisKey(ctx, key, kt, value)
+		if err == nil {
+			http.Redirect(w, r, basePath+"/redis-data/key?k="+encodeKey(key), http.StatusSeeOther)
+			return
+		}
+		notice = `<p style="color:#b91c1c">` + template.HTMLEscapeString(err.Error()) + `</p>`
+	}
+	if kt == "" {
+		kt = "string"
+	}
+
+	var options string
+	for _, t := range []string{"string", "list", "hash", "set", "zset"} {
+		sel := ""
+		if t == kt {
+			sel = " selected"
+		}
+		options += fmt.Sprintf(`<option value="%s"%s>%s</option>`, t, sel, t)
+	}
+
+	content := fmt.Sprintf(`
+<div class="card">
+  <h2>＋ New Redis Key</h2>
+  %s
+  <form method="post">%s
+    <div class="row">
+      <input name="key" class="search" placeholder="Key name" value="%s" required/>
+      <select name="type">%s</select>
+    </div>
+    <p style="color:#6b7280;font-size:13px">
+      string: raw text or a JSON string · list/set: JSON array, e.g. ["a","b"] ·
+      hash: JSON object, e.g. {"field":"value"} · zset: JSON object of member to score, e.g. {"a":1}
+    </p>
+    <textarea name="value" class="json" style="width:100%%;min-height:160px;box-sizing:border-box">%s</textarea>
+    <div style="margin-top:10px"><button class="copy-btn" type="submit">Create</button> <a href="/redis-data">Cancel</a></div>
+  </form>
+</div>
+`, notice, csrfField(), template.HTMLEscapeString(key), options, template.HTMLEscapeString(value))
+
+	page := layout("New Redis Key", content, backendStatus())
+	fmt.Fprint(w, page)
+}
+
+// createRedisKey creates key as type kt with initial values parsed from
+// value, failing if the key already exists or the value doesn't fit the type.
+func createRedisKey(ctx context.Context, key, kt, value string) error {
+	if key == "" {
+		return fmt.Errorf("key name is required")
+	}
+	n, err := redisClient().Exists(ctx, key).Result()
+	if err != nil {
+		return backendErr("redis", err)
+	}
+	if n > 0 {
+		return fmt.Errorf("key %q already exists", key)
+	}
+
+	switch kt {
+	case "string":
+		// accept a JSON string literal, otherwise store the text as is
+		var sv string
+		if json.Unmarshal([]byte(value), &sv) != nil {
+			sv = value
+		}
+		return backendErr("redis", redisClient().Set(ctx, key, sv, 0).Err())
+	case "list", "set":
+		var elems []string
+		if err := json.Unmarshal([]byte(value), &elems); err != nil || len(elems) == 0 {
+			return fmt.Errorf("%s value must be a non-empty JSON array of strings", kt)
+		}
+		args := make([]interface{}, len(elems))
+		for i, e := range elems {
+			args[i] = e
+		}
+		if kt == "list" {
+			return backendErr("redis", redisClient().RPush(ctx, key, args...).Err())
+		}
+		return backendErr("redis", redisClient().SAdd(ctx, key, args...).Err())
+	case "hash":
+		var fields map[string]string
+		if err := json.Unmarshal([]byte(value), &fields); err != nil || len(fields) == 0 {
+			return fmt.Errorf("hash value must be a non-empty JSON object of strings")
+		}
+		return backendErr("redis", redisClient().HSet(ctx, key, fields).Err())
+	case "zset":
+		var scores map[string]float64
+		if err := json.Unmarshal([]byte(value), &scores); err != nil || len(scores) == 0 {
+			return fmt.Errorf("zset value must be a non-empty JSON object of member to score")
+		}
+		members := make([]redis.Z, 0, len(scores))
+		for m, sc := range scores {
+			members = append(members, redis.Z{Score: sc, Member: m})
+		}
+		return backendErr("redis", redisClient().ZAdd(ctx, key, members...).Err())
+	default:
+		return fmt.Errorf("unsupported type %q", kt)
+	}
+}
+
+// hashFieldEditor renders a set/delete form per field of a hash, plus a row
+// to add a field, when Redis writes are enabled. Redacted fields can be
+// deleted but not edited, since their shown value is the mask.
+func hashFieldEditor(key, kt string, fields map[string]string) string {
+	if !redisWrite || kt != "hash" {
+		return ""
+	}
+	names := make([]string, 0, len(fields))
+	for f := range fields {
+		names = append(names, f)
+	}
+	sort.Strings(names)
+
+	ek, csrf := encodeKey(key), csrfField()
+	var rows string
+	for _, f := range names {
+		ef := template.HTMLEscapeString(f)
+		edit := `<span style="flex:1;color:#6b7280">` + redactedValue + `</span>`
+		if !redactedField(f) {
+			edit = fmt.Sprintf(`<form method="post" action="/redis-data/hset" style="flex:1;display:flex;gap:6px">
+        <input type="hidden" name="k" value="%s"/><input type="hidden" name="field" value="%s"/>%s
+        <input name="value" class="search" value="%s"/>
+        <button class="copy-btn" type="submit">Save</button>
+      </form>`, ek, ef, csrf, template.HTMLEscapeString(fields[f]))
+		}
+		rows += fmt.Sprintf(`
+    <div class="list-item">
+      <div style="min-width:160px"><b>%s</b></div>
+      %s
+      <form method="post" action="/redis-data/hdel">
+        <input type="hidden" name="k" value="%s"/><input type="hidden" name="field" value="%s"/>%s
+        <button class="copy-btn" type="submit">Delete</button>
+      </form>
+    </div>`, ef, edit, ek, ef, csrf)
+	}
+
+	return fmt.Sprintf(`
+  <h3>Edit fields</h3>
+  <div class="list">%s
+    <div class="list-item">
+      <form method="post" action="/redis-data/hset" style="flex:1;display:flex;gap:6px">
+        <input type="hidden" name="k" value="%s"/>%s
+        <input name="field" class="search" style="max-width:200px" placeholder="New field" required/>
+        <input name="value" class="search" placeholder="Value"/>
+        <button class="copy-btn" type="submit">Add</button>
+      </form>
+    </div>
+  </div>`, rows, ek, csrf)
+}
+
+// redisHSetHandler sets one field of an existing hash and returns to the
+// key page. Only available with ALLOW_WRITES=true.
+func redisHSetHandler(w http.ResponseWriter, r *http.Request) {
+	redisHashWrite(w, r, func(ctx context.Context, key, field string) error {
+		if redactedField(field) {
+			return fmt.Errorf("field %q is redacted and can't be edited here", field)
+		}
+		return backendErr("redis", redisClient().HSet(ctx, key, field, r.FormValue("value")).Err())
+	})
+}
+
+// redisHDelHandler deletes one field of a hash and returns to the key page.
+// Only available with ALLOW_WRITES=true.
+func redisHDelHandler(w http.ResponseWriter, r *http.Request) {
+	redisHashWrite(w, r, func(ctx context.Context, key, field string) error {
+		return backendErr("redis", redisClient().HDel(ctx, key, field).Err())
+	})
+}
+
+// redisHashWrite checks a per-field hash write request, including its CSRF
+// token, and runs op on it. The key must already be a hash, so a typo can't
+// create a new key.
+func redisHashWrite(w http.ResponseWriter, r *http.Request, op func(ctx context.Context, key, field string) error) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if redisClient() == nil {
+		http.Error(w, "redis not configured", 503)
+		return
+	}
+	if !redisWrite {
+		http.Error(w, "redis writes are disabled (ALLOW_WRITES)", http.StatusForbidden)
+		return
+	}
+	if !parseForm(w, r) {
+		return
+	}
+	if !checkCSRF(r) {
+		http.Error(w, "invalid or missing CSRF token — reload the page and try again", http.StatusForbidden)
+		return
+	}
+	key, field := requestKey(r), r.FormValue("field")
+	if key == "" || field == "" {
+		http.Error(w, "missing key or field", 400)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), backendTimeout)
+	defer cancel()
+	kt, err := redisClient().Type(ctx, key).Result()
+	countBackendError("redis", err)
+	if err == nil && kt != "hash" {
+		err = fmt.Errorf("key %q is a %s, not a hash", key, kt)
+	}
+	if err == nil {
+		err = op(ctx, key, field)
+	}
+	if err != nil {
+		content := `<div class="card"><h2>Edit Hash</h2><p style="color:#b91c1c">` + template.HTMLEscapeString(err.Error()) +
+			`</p><a href="/redis-data/key?k=` + encodeKey(key) + `">← Back to key</a></div>`
+		page := layout("Edit Hash", content, backendStatus())
+		fmt.Fprint(w, page)
+		return
+	}
+	http.Redirect(w, r, basePath+"/redis-data/key?k="+encodeKey(key), http.StatusSeeOther)
+}
+
+// keyDeleteButton renders the "Delete key" form of the key page when Redis
+// writes are enabled.
+func keyDeleteButton(key string) string {
+	if !redisWrite {
+		return ""
+	}
+	jk, _ := json.Marshal(key)
+	return fmt.Sprintf(`<form method="post" action="/redis-data/delete" style="display:inline;margin-left:8px" onsubmit="return confirm('Delete key ' + %s + '? This cannot be undone.')">
+      <input type="hidden" name="k" value="%s"/>%s
+      <button class="copy-btn" type="submit" style="background:#b91c1c">Delete key</button>
+    </form>`, template.HTMLEscapeString(string(jk)), encodeKey(key), csrfField())
+}
+
+// redisDeleteHandler deletes a key and returns to the key list. Only
+// available with Redis writes enabled, and only for forms carrying the CSRF
+// token.
+func redisDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if redisClient() == nil {
+		http.Error(w, "redis not configured", 503)
+		return
+	}
+	if !redisWrite {
+		http.Error(w, "redis writes are disabled (ALLOW_WRITES)", http.StatusForbidden)
+		return
+	}
+	if !parseForm(w, r) {
+		return
+	}
+	if !checkCSRF(r) {
+		http.Error(w, "invalid or missing CSRF token — reload the page and try again", http.StatusForbidden)
+		return
+	}
+	key := requestKey(r)
+	if key == "" {
+		http.Error(w, "missing key param", 400)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), backendTimeout)
+	defer cancel()
+	n, err := redisClient().Del(ctx, key).Result()
+	countBackendError("redis", err)
+	if err == nil && n == 0 {
+		err = fmt.Errorf("key %q no longer exists — nothing was deleted", key)
+	}
+	if err != nil {
+		content := `<div class="card"><h2>Delete Key</h2><p style="color:#b91c1c">` + template.HTMLEscapeString(err.Error()) +
+			`</p><a href="/redis-data">← Keys</a></div>`
+		page := layout("Delete Key", content, backendStatus())
+		fmt.Fprint(w, page)
+		return
+	}
+	slog.Info("redis key deleted", "key", key, "remote_addr", r.RemoteAddr)
+	http.Redirect(w, r, basePath+"/redis-data", http.StatusSeeOther)
+}
+
+// flushConfirmWindow is the confirmation window of /redis-data/flush when
+// CONFIRM_DESTRUCTIVE_SECONDS is not set; flushing always asks twice.
+const flushConfirmWindow = 30 * time.Second
+
+// redisFlushHandler clears the configured Redis database with FLUSHDB
+// (never FLUSHALL). It needs ALLOW_WRITES and ALLOW_FLUSH, the CSRF
+// token, the DB number typed into the form and the timed second
+// confirmation.
+func redisFlushHandler(w http.ResponseWriter, r *http.Request) {
+	if redisClient() == nil {
+		content := `<div class="card"><h2>Flush Redis DB</h2><p style="color:#6b7280">Redis not configured.</p></div>`
+		page := layout("Flush Redis DB", content, backendStatus())
+		fmt.Fprint(w, page)
+		return
+	}
+	if !redisWrite || !redisFlush {
+		content := `<div class="card"><h2>Flush Redis DB</h2><p style="color:#6b7280">Flushing is disabled. It needs both <code>ALLOW_WRITES=true</code> and <code>ALLOW_FLUSH=true</code>.</p></div>`
+		page := layout("Flush Redis DB", content, backendStatus())
+		fmt.Fprint(w, page)
+		return
+	}
+	if redisCluster {
+		content := `<div class="card"><h2>Flush Redis DB</h2><p style="color:#6b7280">Flushing isn't available against a Redis cluster.</p></div>`
+		page := layout("Flush Redis DB", content, backendStatus())
+		fmt.Fprint(w, page)
+		return
+	}
+	if !parseForm(w, r) {
+		return
+	}
+
+	db := strconv.Itoa(redisClient().(*redis.Client).Options().DB)
+	notice := ""
+	if r.Method == http.MethodPost {
+		if !checkCSRF(r) {
+			http.Error(w, "invalid or missing CSRF token — reload the page and try again", http.StatusForbidden)
+			return
+		}
+		if r.PostForm.Get("db") == db {
+			window := confirmWindow
+			if window == 0 {
+				window = flushConfirmWindow
+			}
+			confirmStep(window, func(*http.Request) string {
+				return "Flush Redis DB " + db + "? Every key in it will be deleted."
+			}, func(w http.ResponseWriter, r *http.Request) {
+				ctx, cancel := context.WithTimeout(r.Context(), backendTimeout)
+				defer cancel()
+				if err := backendErr("redis", redisClient().FlushDB(ctx).Err()); err != nil {
+					content := `<div class="card"><h2>Flush Redis DB</h2><p style="color:#b91c1c">` + template.HTMLEscapeString(err.Error()) + `</p></div>`
+					page := layout("Flush Redis DB", content, backendStatus())
+					fmt.Fprint(w, page)
+					return
+				}
+				slog.Info("redis db flushed", "db", db, "remote_addr", r.RemoteAddr)
+				http.Redirect(w, r, basePath+"/redis-data", http.StatusSeeOther)
+			})(w, r)
+			return
+		}
+		notice = `<p style="color:#b91c1c">The number typed doesn't match the selected DB — nothing was flushed.</p>`
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), backendTimeout)
+	defer cancel()
+	size, err := redisClient().DBSize(ctx).Result()
+	countBackendError("redis", err)
+	sizeText := "an unknown number of"
+	if err == nil {
+		sizeText = strconv.FormatInt(size, 10)
+	}
+
+	content := fmt.Sprintf(`
+<div class="card" style="border:2px solid #b91c1c">
+  <h2 style="color:#b91c1c">🛑 Flush Redis DB %s</h2>
+  <p style="color:#b91c1c"><b>This deletes all %s keys in DB %s permanently.</b> Other databases are not touched.</p>
+  %s
+  <form method="post">%s
+    <div class="row">
+      <input name="db" class="search" style="max-width:260px" placeholder="Type %s to confirm" autocomplete="off" required/>
+      <button class="copy-btn" type="submit" style="background:#b91c1c">Flush DB</button>
+      <a href="/redis-data">Cancel</a>
+    </div>
+  </form>
+</div>
+`, db, sizeText, db, notice, csrfField(), db)
+
+	page := layout("Flush Redis DB", content, backendStatus())
+	fmt.Fprint(w, page)
+}
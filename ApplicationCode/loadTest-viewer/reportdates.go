@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// reportRange bounds report modification times for ?from= and ?to=. Both
+// ends are inclusive; a zero end is open.
+type reportRange struct {
+	from, to time.Time
+}
+
+func (rr reportRange) contains(t time.Time) bool {
+	if !rr.from.IsZero() && t.Before(rr.from) {
+		return false
+	}
+	return rr.to.IsZero() || !t.After(rr.to)
+}
+
+// parseReportDate accepts an RFC3339 timestamp or a YYYY-MM-DD day (UTC,
+// as on the activity page). A day used as the upper bound covers all of it.
+func parseReportDate(raw string, end bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	d, err := time.Parse("2006-01-02", raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not a date: use YYYY-MM-DD or RFC3339", raw)
+	}
+	if end {
+		d = d.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return d, nil
+}
+
+// requestReportRange reads ?from= and ?to=. An invalid bound is left open
+// and described in problems, so the page can say why it was ignored.
+func requestReportRange(r *http.Request) (rr reportRange, problems []string) {
+	if v := r.URL.Query().Get("from"); v != "" {
+		t, err := parseReportDate(v, false)
+		if err != nil {
+			problems = append(problems, "from: "+err.Error())
+		}
+		rr.from = t
+	}
+	if v := r.URL.Query().Get("to"); v != "" {
+		t, err := parseReportDate(v, true)
+		if err != nil {
+			problems = append(problems, "to: "+err.Error())
+		}
+		rr.to = t
+	}
+	return rr, problems
+}
+
+// dateInputValue is a ?from=/?to= value as a date picker can show it.
+func dateInputValue(raw string) string {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t.UTC().Format("2006-01-02")
+	}
+	if _, err := time.Parse("2006-01-02", raw); err == nil {
+		return raw
+	}
+	return ""
+}
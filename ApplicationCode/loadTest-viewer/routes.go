@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+)
+
+// viewer is a section of the UI: its sidebar link and its routes. Adding a
+// viewer (say Postgres or Kafka) means writing one of these and listing it
+// in viewers; main and layout pick it up from there.
+type viewer struct {
+	Nav    string // sidebar label
+	Path   string // sidebar link
+	ID     string // element id of the sidebar link
+	Status func(BackendStatus) (backend, state string)
+	Badge  func() string // optional label after the link, "" for none
+	Routes func(mux *http.ServeMux)
+}
+
+// viewers in sidebar order. They are set in init because their routes
+// render pages through layout, which reads viewers.
+var viewers []viewer
+
+func init() {
+	viewers = []viewer{
+		{
+			Nav:    "📊 Load Test Reports",
+			Path:   "/load-test",
+			ID:     "nav-load",
+			Status: func(s BackendStatus) (string, string) { return "S3", s.S3 },
+			Badge:  bucketBadge,
+			Routes: reportRoutes,
+		},
+		{
+			Nav:    "🗄 MongoDB Viewer",
+			Path:   "/db-data",
+			ID:     "nav-db",
+			Status: func(s BackendStatus) (string, string) { return "MongoDB", s.Mongo },
+			Routes: mongoRoutes,
+		},
+		{
+			Nav:    "⚡ Redis Viewer",
+			Path:   "/redis-data",
+			ID:     "nav-redis",
+			Status: func(s BackendStatus) (string, string) { return "Redis", s.Redis },
+			Routes: redisRoutes,
+		},
+	}
+}
+
+// registerRoutes registers every viewer's routes plus the shared ones.
+func registerRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/healthz", healthzHandler)
+	mux.HandleFunc("/readyz", readyzHandler)
+	mux.Handle("/metrics", metricsHandler())
+	mux.HandleFunc("/inspect", inspectHandler)
+	mux.HandleFunc("/export/bundle", exportBundleHandler)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		// default redirect to the first viewer
+		http.Redirect(w, r, basePath+viewers[0].Path, http.StatusFound)
+	})
+
+	for _, v := range viewers {
+		v.Routes(mux)
+	}
+
+	// JSON API for other frontends; the only routes with CORS (CORS_ORIGINS)
+	mux.HandleFunc("/api/load-test", withCORS(apiLoadTestHandler))
+	mux.HandleFunc("/api/reports/recent", withCORS(recentReportsHandler))
+	mux.HandleFunc("/api/reports/index", withCORS(reportIndexHandler))
+	mux.HandleFunc("/api/status", withCORS(readyzHandler))
+}
+
+func reportRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/load-test", loadTestHandler)
+	mux.HandleFunc("/load-test/recent", recentReportsHandler)
+	mux.HandleFunc("/load-test/proxy", reportProxyHandler)
+	mux.HandleFunc("/load-test/open", reportOpenHandler)
+	mux.HandleFunc("/load-test/index", reportIndexHandler)
+	mux.HandleFunc("/load-test/search", reportSearchHandler)
+	mux.HandleFunc("/load-test/share", reportShareHandler)
+	mux.HandleFunc("/load-test/activity", reportActivityHandler)
+	mux.HandleFunc("/load-test/s/", sharedReportHandler)
+	mux.HandleFunc("/load-test/object", objectHandler)
+	mux.HandleFunc("/load-test/refresh", cacheRefreshHandler)
+	if reportsDir != "" {
+		mux.Handle("/load-test/files/", localFilesHandler())
+	}
+}
+
+func mongoRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/db-data", dbDataHandler)
+	mux.HandleFunc("/db-data/collection", dbCollectionHandler)
+	mux.HandleFunc("/db-data/watch", dbWatchHandler)
+	mux.HandleFunc("/db-data/watch/events", dbWatchEventsHandler)
+	mux.HandleFunc("/db-data/validate", dbValidateHandler)
+	mux.HandleFunc("/db-data/document", dbDocumentHandler)
+}
+
+func redisRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/redis-data", redisDataHandler)
+	mux.HandleFunc("/redis-data/key", redisKeyHandler)
+	mux.HandleFunc("/redis-data/download", redisDownloadHandler)
+	mux.HandleFunc("/redis-data/search", redisSearchHandler)
+	mux.HandleFunc("/redis-data/create", redisCreateHandler)
+	mux.HandleFunc("/redis-data/hset", redisHSetHandler)
+	mux.HandleFunc("/redis-data/hdel", confirmed(func(r *http.Request) string {
+		return fmt.Sprintf("Delete field %q of hash %q?", r.FormValue("field"), requestKey(r))
+	}, redisHDelHandler))
+	mux.HandleFunc("/redis-data/delete", confirmed(func(r *http.Request) string {
+		return fmt.Sprintf("Delete key %q?", requestKey(r))
+	}, redisDeleteHandler))
+	mux.HandleFunc("/redis-data/export", redisExportHandler)
+	mux.HandleFunc("/redis-data/flush", redisFlushHandler)
+}
+
+// bucketBadge names the default bucket next to the reports link when there
+// is more than one to choose from. Pages showing another ?bucket= swap the
+// label client-side.
+func bucketBadge() string {
+	if len(s3Buckets) < 2 {
+		return ""
+	}
+	return s3Bucket
+}
+
+// navLinks renders the sidebar links of the viewers with their
+// connectivity dots and badges.
+func navLinks(status BackendStatus) string {
+	var b strings.Builder
+	for _, v := range viewers {
+		dot := ""
+		if v.Status != nil {
+			backend, state := v.Status(status)
+			dot = fmt.Sprintf(`<span class="dot dot-%s" title="%s: %s"></span>`,
+				template.HTMLEscapeString(state), template.HTMLEscapeString(backend), template.HTMLEscapeString(state))
+		}
+		badge := ""
+		if v.Badge != nil {
+			if label := v.Badge(); label != "" {
+				badge = fmt.Sprintf(`<span class="nav-badge" id="%s-badge">%s</span>`,
+					template.HTMLEscapeString(v.ID), template.HTMLEscapeString(label))
+			}
+		}
+		fmt.Fprintf(&b, "        <a href=\"%s\" id=\"%s\">%s%s%s</a>\n",
+			template.HTMLEscapeString(v.Path), template.HTMLEscapeString(v.ID), template.HTMLEscapeString(v.Nav), dot, badge)
+	}
+	return b.String()
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// validateDoc checks v against a JSON Schema and appends a "path: rule"
+// message for each violation. It supports the common keywords: type, enum,
+// const, required, properties, additionalProperties, items, minItems,
+// maxItems, minLength, maxLength, pattern, minimum and maximum.
+func validateDoc(schema map[string]interface{}, v interface{}, path string, out *[]string) {
+	fail := func(format string, args ...interface{}) {
+		*out = append(*out, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	if t, ok := schema["type"]; ok && !matchesType(t, v) {
+		fail("expected type %v, got %s", t, jsonType(v))
+		return
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, e := range enum {
+			if jsonEqual(e, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("value not in enum")
+		}
+	}
+	if c, ok := schema["const"]; ok && !jsonEqual(c, v) {
+		fail("value must be %v", c)
+	}
+
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if req, ok := schema["required"].([]interface{}); ok {
+			for _, r := range req {
+				if name, ok := r.(string); ok {
+					if _, present := val[name]; !present {
+						fail("missing required field %q", name)
+					}
+				}
+			}
+		}
+		props, _ := schema["properties"].(map[string]interface{})
+		for name, fv := range val {
+			if ps, ok := props[name].(map[string]interface{}); ok {
+				validateDoc(ps, fv, path+"."+name, out)
+			} else if ap, ok := schema["additionalProperties"]; ok {
+				switch a := ap.(type) {
+				case bool:
+					if !a {
+						fail("unexpected field %q", name)
+					}
+				case map[string]interface{}:
+					validateDoc(a, fv, path+"."+name, out)
+				}
+			}
+		}
+	case []interface{}:
+		if n, ok := schema["minItems"].(float64); ok && float64(len(val)) < n {
+			fail("expected at least %v items, got %d", n, len(val))
+		}
+		if n, ok := schema["maxItems"].(float64); ok && float64(len(val)) > n {
+			fail("expected at most %v items, got %d", n, len(val))
+		}
+		if is, ok := schema["items"].(map[string]interface{}); ok {
+			for i, e := range val {
+				validateDoc(is, e, fmt.Sprintf("%s[%d]", path, i), out)
+			}
+		}
+	case string:
+		n := float64(len([]rune(val)))
+		if m, ok := schema["minLength"].(float64); ok && n < m {
+			fail("shorter than %v characters", m)
+		}
+		if m, ok := schema["maxLength"].(float64); ok && n > m {
+			fail("longer than %v characters", m)
+		}
+		if p, ok := schema["pattern"].(string); ok {
+			if re, err := regexp.Compile(p); err == nil && !re.MatchString(val) {
+				fail("does not match pattern %s", p)
+			}
+		}
+	case float64:
+		if m, ok := schema["minimum"].(float64); ok && val < m {
+			fail("less than minimum %v", m)
+		}
+		if m, ok := schema["maximum"].(float64); ok && val > m {
+			fail("greater than maximum %v", m)
+		}
+	}
+}
+
+// matchesType reports whether v has the schema type t (a name or a list).
+func matchesType(t interface{}, v interface{}) bool {
+	switch tt := t.(type) {
+	case string:
+		got := jsonType(v)
+		return got == tt || (tt == "number" && got == "integer")
+	case []interface{}:
+		for _, e := range tt {
+			if matchesType(e, v) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func jsonType(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if val == math.Trunc(val) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func jsonEqual(a, b interface{}) bool {
+	ja, _ := json.Marshal(a)
+	jb, _ := json.Marshal(b)
+	return string(ja) == string(jb)
+}
+
+// docViolations is a sampled document that failed validation.
+type docViolations struct {
+	ID     string
+	Issues []string
+}
+
+// validatePage renders the schema validation form.
+const validatePage = `
+<div class="card">
+  <h2>✔ Validate: {{.Name}} ({{.DB}})</h2>
+  <div style="margin-bottom:10px"><a href="/db-data/collection?db={{.DB}}&name={{.Name}}">← {{.Name}}</a></div>
+  <form method="post">
+    <input type="hidden" name="db" value="{{.DB}}"/>
+    <input type="hidden" name="name" value="{{.Name}}"/>
+    <div class="row" style="color:#6b7280;font-size:13px">
+      Sample:
+      <select name="sample">
+        {{range .Strategies}}<option value="{{.}}"{{if eq . $.Sample}} selected{{end}}>{{.}}</option>{{end}}
+      </select>
+      up to {{.Max}} documents
+    </div>
+    <textarea name="schema" class="json" style="width:100%;min-height:200px;box-sizing:border-box" placeholder='{"type":"object","required":["status"],"properties":{"status":{"enum":["ok","failed"]}}}'>{{.Schema}}</textarea>
+    <div style="margin-top:10px"><button class="copy-btn" type="submit">Validate</button></div>
+  </form>
+  {{if .Notice}}<p style="color:#b91c1c">{{.Notice}}</p>{{end}}
+
+  {{if .Posted}}{{if not .Notice}}
+  <h3>{{len .Bad}} of {{.Checked}} documents do not conform</h3>
+  <div class="list">
+  {{range .Bad}}
+    <div class="list-item" style="display:block">
+      <div><b>_id {{.ID}}</b></div>
+      <ul style="margin:4px 0;color:#b91c1c;font-size:13px">{{range .Issues}}<li>{{.}}</li>{{end}}</ul>
+    </div>
+  {{end}}
+  </div>
+  {{end}}{{end}}
+</div>
+`
+
+// dbValidateHandler checks a sample of a collection against a JSON Schema
+// pasted into the form and lists the non-conforming documents by _id.
+// Documents are compared in relaxed extended JSON, so ObjectIds and dates
+// are objects ({"$oid": ...}, {"$date": ...}).
+func dbValidateHandler(w http.ResponseWriter, r *http.Request) {
+	if mongoClient() == nil {
+		content := `<div class="card"><h2>Validate</h2><p style="color:#6b7280">Mongo not configured.</p></div>`
+		page := layout("Validate", content, backendStatus())
+		fmt.Fprint(w, page)
+		return
+	}
+	if !parseForm(w, r) {
+		return
+	}
+
+	name := r.FormValue("name")
+	if name == "" {
+		http.Error(w, "missing collection name", 400)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), backendTimeout)
+	defer cancel()
+	dbName, err := requestDB(ctx, r)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	rawSchema := r.FormValue("schema")
+	strategy := sampleStrategyOf(r.FormValue("sample"))
+	var (
+		notice  string
+		checked int
+		bad     []docViolations
+	)
+	if r.Method == http.MethodPost {
+		checked, bad, err = validateSample(ctx, dbName, name, rawSchema, strategy)
+		if isTimeout(err) {
+			renderTimeout(w, "Validate: "+name)
+			return
+		}
+		if err != nil {
+			notice = err.Error()
+		}
+	}
+
+	renderPage(w, "validate", map[string]interface{}{
+		"DB":         dbName,
+		"Name":       name,
+		"Schema":     rawSchema,
+		"Sample":     strategy,
+		"Strategies": []string{"oldest", "latest", "random"},
+		"Max":        limits.MongoPageSize,
+		"Posted":     r.Method == http.MethodPost,
+		"Notice":     notice,
+		"Checked":    checked,
+		"Bad":        bad,
+	})
+}
+
+// validateSample samples up to limits.MongoPageSize documents with the
+// given strategy and validates each against rawSchema.
+func validateSample(ctx context.Context, dbName, name, rawSchema, strategy string) (int, []docViolations, error) {
+	var schema map[string]interface{}
+	if err := json.Unmarshal([]byte(rawSchema), &schema); err != nil {
+		return 0, nil, fmt.Errorf("invalid schema: %v", err)
+	}
+
+	coll := mongoClient().Database(dbName).Collection(name)
+	cur, err := sampleDocs(ctx, coll, bson.M{}, strategy, 0, limits.MongoPageSize)
+	if err != nil {
+		return 0, nil, err
+	}
+	smp, err := readSample(ctx, cur)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var bad []docViolations
+	for _, doc := range smp.docs {
+		ej, err := bson.MarshalExtJSON(doc, false, false)
+		if err != nil {
+			continue
+		}
+		var v interface{}
+		if err := json.Unmarshal(ej, &v); err != nil {
+			continue
+		}
+		var issues []string
+		validateDoc(schema, v, "$", &issues)
+		if len(issues) > 0 {
+			sort.Strings(issues)
+			bad = append(bad, docViolations{ID: docID(doc["_id"]), Issues: issues})
+		}
+	}
+	return len(smp.docs), bad, nil
+}
+
+// docID renders an _id for display.
+func docID(id interface{}) string {
+	if oid, ok := id.(primitive.ObjectID); ok {
+		return oid.Hex()
+	}
+	return fmt.Sprint(id)
+}
+
+// validateLink links a collection view to its validation page.
+func validateLink(dbName, name string) string {
+	return `<a href="/db-data/validate?` + template.HTMLEscapeString(url.Values{"db": {dbName}, "name": {name}}.Encode()) + `" style="margin-left:8px">✔ Validate</a>`
+}
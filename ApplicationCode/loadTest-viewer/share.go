@@ -0,0 +1,117 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// sharePresignTTL is how long the presign behind a share link lives; the
+// share link itself never expires.
+const sharePresignTTL = 15 * time.Minute
+
+// shareSecret signs share tokens. Without SHARE_SECRET a random secret is
+// used, so links only survive until the next restart.
+var shareSecret []byte
+
+func loadShareSecret(s string) {
+	if s != "" {
+		shareSecret = []byte(s)
+		return
+	}
+	shareSecret = make([]byte, 32)
+	rand.Read(shareSecret)
+	slog.Warn("SHARE_SECRET not set — share links are valid until restart")
+}
+
+// shareToken returns a stable token naming bucket/key: the payload plus a
+// truncated HMAC, both base64url.
+func shareToken(bucket, key string) string {
+	payload := bucket + "\x00" + key
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString([]byte(payload)) + "." + enc.EncodeToString(shareMAC(payload))
+}
+
+// parseShareToken verifies a token and returns the bucket and key it names.
+func parseShareToken(token string) (bucket, key string, ok bool) {
+	p, sig, found := strings.Cut(token, ".")
+	if !found {
+		return "", "", false
+	}
+	enc := base64.RawURLEncoding
+	payload, err := enc.DecodeString(p)
+	if err != nil {
+		return "", "", false
+	}
+	mac, err := enc.DecodeString(sig)
+	if err != nil || !hmac.Equal(mac, shareMAC(string(payload))) {
+		return "", "", false
+	}
+	bucket, key, found = strings.Cut(string(payload), "\x00")
+	return bucket, key, found
+}
+
+func shareMAC(payload string) []byte {
+	m := hmac.New(sha256.New, shareSecret)
+	m.Write([]byte(payload))
+	return m.Sum(nil)[:16]
+}
+
+// reportShareHandler shows a durable share link for a report.
+func reportShareHandler(w http.ResponseWriter, r *http.Request) {
+	if s3Client() == nil || s3Presign() == nil {
+		http.Error(w, "S3 not configured", 503)
+		return
+	}
+	key := r.URL.Query().Get("key")
+	if key == "" {
+		http.Error(w, "missing key param", 400)
+		return
+	}
+	bucket, ok := requestBucket(r)
+	if !ok {
+		http.Error(w, "bucket not allowed", 400)
+		return
+	}
+
+	link := requestBaseURL(r) + basePath + "/load-test/s/" + shareToken(bucket, key)
+	content := fmt.Sprintf(`
+<div class="card">
+  <h2>🔗 Share: %s</h2>
+  <p style="color:#6b7280">This link doesn't expire. Each visit redirects to a presigned URL valid for %s.</p>
+  <pre id="shareLink" class="json">%s</pre>
+  <button class="copy-btn" onclick="copyTextById('shareLink')">Copy link</button>
+  <a href="/load-test" style="margin-left:8px">← Reports</a>
+</div>
+`, template.HTMLEscapeString(key), sharePresignTTL, template.HTMLEscapeString(link))
+
+	page := layout("Share: "+key, content, backendStatus())
+	fmt.Fprint(w, page)
+}
+
+// sharedReportHandler resolves /load-test/s/<token> to a fresh short-lived
+// presign of the report it names.
+func sharedReportHandler(w http.ResponseWriter, r *http.Request) {
+	if s3Client() == nil || s3Presign() == nil {
+		http.Error(w, "S3 not configured", 503)
+		return
+	}
+	bucket, key, ok := parseShareToken(strings.TrimPrefix(r.URL.Path, "/load-test/s/"))
+	if !ok || !allowedBucket(bucket) {
+		http.Error(w, "invalid share link", 404)
+		return
+	}
+	u, err := presignReportFor(r.Context(), bucket, key, "", sharePresignTTL)
+	if err != nil {
+		http.Error(w, "Failed to presign report: "+err.Error(), 500)
+		return
+	}
+	http.Redirect(w, r, u, http.StatusFound)
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// shutdownGrace is how long in-flight requests get to finish after SIGTERM
+// or SIGINT (SHUTDOWN_GRACE). Keep it below the pod's
+// terminationGracePeriodSeconds.
+var shutdownGrace = 20 * time.Second
+
+// server is an http.Server and how to start it.
+type server struct {
+	*http.Server
+	certFile, keyFile string // serve TLS when set
+}
+
+func (s server) listen() error {
+	if s.certFile != "" {
+		return s.ListenAndServeTLS(s.certFile, s.keyFile)
+	}
+	return s.ListenAndServe()
+}
+
+// runServers serves until a server fails or the process is asked to stop,
+// then shuts the servers down within shutdownGrace and closes the backend
+// clients. A server failure exits non-zero once cleaned up.
+func runServers(servers []server) {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	errc := make(chan error, len(servers))
+	for _, s := range servers {
+		go func() {
+			if err := s.listen(); !errors.Is(err, http.ErrServerClosed) {
+				errc <- err
+			}
+		}()
+	}
+
+	var failed error
+	select {
+	case failed = <-errc:
+		slog.Error("shutdown: server failed", "error", failed)
+	case <-ctx.Done():
+		slog.Info("shutdown: signal received, draining requests", "grace", shutdownGrace.String())
+	}
+
+	sctx, cancel := context.WithTimeout(context.Background(), shutdownGrace)
+	defer cancel()
+	for _, s := range servers {
+		if err := s.Shutdown(sctx); err != nil {
+			// long-lived streams (the watch view) never go idle
+			slog.Warn("shutdown: closing remaining connections", "addr", s.Addr, "error", err)
+			s.Close()
+		}
+	}
+	slog.Info("shutdown: http servers stopped")
+
+	closeClients()
+	if failed != nil {
+		os.Exit(1)
+	}
+	slog.Info("shutdown: complete")
+}
+
+// closeClients disconnects Mongo and closes the Redis pool.
+func closeClients() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if c := mongoClient(); c != nil {
+		if err := c.Disconnect(ctx); err != nil {
+			slog.Error("shutdown: mongo disconnect failed", "backend", "mongo", "error", err)
+		} else {
+			slog.Info("shutdown: mongo disconnected")
+		}
+	}
+	if c := redisClient(); c != nil {
+		if err := c.Close(); err != nil {
+			slog.Error("shutdown: redis close failed", "backend", "redis", "error", err)
+		} else {
+			slog.Info("shutdown: redis closed")
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// backend states shown in the sidebar
+const (
+	statusOK   = "ok"
+	statusDown = "down"
+	statusOff  = "off" // not configured
+
+	statusUnknown = "unknown" // status loop stalled
+)
+
+// BackendStatus is the connectivity of each backend as shown in the sidebar.
+type BackendStatus struct {
+	S3    string
+	Mongo string
+	Redis string
+}
+
+// the background loop re-checks every statusInterval; a result older than
+// statusStaleAfter means the loop has stalled and is reported as unknown
+var (
+	statusInterval   = 30 * time.Second
+	statusStaleAfter = 3 * statusInterval
+)
+
+// statusRefreshMin (STATUS_REFRESH_MIN) is how fresh the status must be for
+// ?nocache=1 to skip re-checking, so refreshes can't hammer the backends.
+var statusRefreshMin = 10 * time.Second
+
+// statusRefreshing is set while a ?nocache=1 re-check runs; concurrent
+// requests use the cached status rather than start another.
+var statusRefreshing atomic.Bool
+
+var (
+	statusMu      sync.Mutex
+	statusCache   BackendStatus
+	statusChecked time.Time
+)
+
+// startStatusLoop checks the backends once, then keeps re-checking them in
+// the background so pages and /readyz never wait on the backends.
+func startStatusLoop() {
+	refreshStatus()
+	go func() {
+		t := time.NewTicker(statusInterval)
+		defer t.Stop()
+		for range t.C {
+			refreshStatus()
+		}
+	}()
+}
+
+func refreshStatus() {
+	st := checkBackends(context.Background())
+	statusMu.Lock()
+	statusCache, statusChecked = st, time.Now()
+	statusMu.Unlock()
+}
+
+// cachedStatus returns the latest result of the status loop and when it was
+// taken. If it is stale every backend is reported as unknown.
+func cachedStatus() (BackendStatus, time.Time) {
+	statusMu.Lock()
+	defer statusMu.Unlock()
+
+	if time.Since(statusChecked) > statusStaleAfter {
+		return BackendStatus{S3: statusUnknown, Mongo: statusUnknown, Redis: statusUnknown}, statusChecked
+	}
+	return statusCache, statusChecked
+}
+
+// refreshOnNoCache re-checks the backends before serving a ?nocache=1
+// request, so a refreshed view shows current status dots. It runs at most
+// once per statusRefreshMin and only for authenticated requests (it sits
+// inside requireAuth).
+func refreshOnNoCache(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if noCache(r) && statusAge() >= statusRefreshMin && statusRefreshing.CompareAndSwap(false, true) {
+			refreshStatus()
+			statusRefreshing.Store(false)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// statusAge is the time since the backends were last checked.
+func statusAge() time.Duration {
+	statusMu.Lock()
+	defer statusMu.Unlock()
+	return time.Since(statusChecked)
+}
+
+// backendStatus returns the cached backend status for the sidebar.
+func backendStatus() BackendStatus {
+	st, _ := cachedStatus()
+	return st
+}
+
+// readyzHandler reports the cached backend status as JSON. It answers 503
+// when a configured backend is down or the status is stale; unconfigured
+// backends are reported as "disabled" and don't affect readiness.
+func readyzHandler(w http.ResponseWriter, r *http.Request) {
+	writeStatus(w, true)
+}
+
+// healthzHandler is the liveness probe: the same JSON as /readyz, but
+// always 200 while the process serves requests, so an unreachable backend
+// never gets the pod restarted.
+func healthzHandler(w http.ResponseWriter, r *http.Request) {
+	writeStatus(w, false)
+}
+
+func writeStatus(w http.ResponseWriter, failUnready bool) {
+	st, checked := cachedStatus()
+	ready := true
+	for _, s := range []string{st.S3, st.Mongo, st.Redis} {
+		if s == statusDown || s == statusUnknown {
+			ready = false
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if failUnready && !ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"ready":     ready,
+		"checkedAt": checked,
+		"s3":        probeState(st.S3),
+		"mongo":     probeState(st.Mongo),
+		"redis":     probeState(st.Redis),
+	})
+}
+
+// probeState names a backend state for the JSON probes.
+func probeState(s string) string {
+	if s == statusOff {
+		return "disabled"
+	}
+	return s
+}
+
+func checkBackends(ctx context.Context) BackendStatus {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var st BackendStatus
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		st.S3 = probe(s3Client() != nil, func() error {
+			_, err := s3Client().HeadBucket(ctx, &s3.HeadBucketInput{Bucket: aws.String(s3Bucket)})
+			return backendErr("s3", err)
+		})
+	}()
+	go func() {
+		defer wg.Done()
+		st.Mongo = probe(mongoClient() != nil, func() error {
+			return backendErr("mongo", mongoClient().Ping(ctx, nil))
+		})
+	}()
+	go func() {
+		defer wg.Done()
+		st.Redis = probe(redisClient() != nil, func() error {
+			return backendErr("redis", redisClient().Ping(ctx).Err())
+		})
+	}()
+	wg.Wait()
+	return st
+}
+
+func probe(configured bool, check func() error) string {
+	if !configured {
+		return statusOff
+	}
+	if check() != nil {
+		return statusDown
+	}
+	return statusOK
+}